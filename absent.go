@@ -4,6 +4,8 @@
 
 package validation
 
+import "context"
+
 var (
 	// ErrNil is the error that returns when a value is not nil.
 	ErrNil = NewError("validation_nil", "must be blank")
@@ -20,13 +22,19 @@ var Empty = absentRule{condition: true, skipNil: true}
 
 type absentRule struct {
 	condition bool
+	lazy      *lazyCondition
 	err       Error
 	skipNil   bool
 }
 
 // Validate checks if the given value is valid or not.
 func (r absentRule) Validate(value interface{}) error {
-	if r.condition {
+	return r.ValidateWithContext(nil, value)
+}
+
+// ValidateWithContext checks if the given value is valid or not.
+func (r absentRule) ValidateWithContext(ctx context.Context, value interface{}) error {
+	if r.lazy.eval(ctx, value, r.condition) {
 		value, isNil := Indirect(value)
 		if !r.skipNil && !isNil || r.skipNil && !isNil && !IsEmpty(value) {
 			if r.err != nil {
@@ -44,6 +52,20 @@ func (r absentRule) Validate(value interface{}) error {
 // When sets the condition that determines if the validation should be performed.
 func (r absentRule) When(condition bool) absentRule {
 	r.condition = condition
+	r.lazy = nil
+	return r
+}
+
+// WhenFunc sets a function that determines at validation time if the validation should be performed.
+// The function is called with the validation context, or context.Background() if there is none.
+func (r absentRule) WhenFunc(condition func(ctx context.Context) bool) absentRule {
+	r.lazy = ctxCondition(condition)
+	return r
+}
+
+// WhenValue sets a predicate that determines if the validation should be performed for the value being validated.
+func (r absentRule) WhenValue(predicate func(value interface{}) bool) absentRule {
+	r.lazy = valueCondition(predicate)
 	return r
 }
 
@@ -5,6 +5,7 @@
 package validation
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -71,6 +72,24 @@ func TestAbsentRule_When(t *testing.T) {
 	assert.Equal(t, ErrNil, err)
 }
 
+func TestAbsentRule_WhenFunc(t *testing.T) {
+	r := Empty.WhenFunc(func(ctx context.Context) bool {
+		return ctx.Value(contains) != nil
+	})
+	assert.Nil(t, Validate("abc", r))
+	assert.Equal(t, ErrEmpty, ValidateWithContext(context.WithValue(context.Background(), contains, "abc"), "abc", r))
+}
+
+func TestAbsentRule_WhenValue(t *testing.T) {
+	r := Nil.WhenValue(func(value interface{}) bool {
+		_, ok := value.(*int)
+		return ok
+	})
+	n := 42
+	assert.Nil(t, Validate(42, r))
+	assert.Equal(t, ErrNil, Validate(&n, r))
+}
+
 func Test_absentRule_Error(t *testing.T) {
 	r := Nil
 	assert.Equal(t, "must be blank", r.Validate("42").Error())
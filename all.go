@@ -0,0 +1,70 @@
+// Copyright 2016 Qiang Xue, 2022 Jellydator. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import "context"
+
+// All returns a validation rule that evaluates all the given rules instead of stopping at the first failing one.
+// If more than one rule fails, their errors are returned as an ErrorList, in the order of the rules.
+// For example, the following rule reports both problems of a password that is too short and has no digits:
+//
+//	validation.All(
+//	    validation.Length(8, 0),
+//	    validation.Match(regexp.MustCompile("[0-9]")).Error("must contain a digit"),
+//	)
+//
+// As with Validate, a Skip rule stops the evaluation of the rules following it, and an InternalError
+// is returned immediately, discarding the errors of the other rules.
+func All(rules ...Rule) AllRule {
+	return AllRule{rules: rules}
+}
+
+// AllRule is a validation rule that evaluates all its rules and collects their errors.
+type AllRule struct {
+	rules []Rule
+}
+
+// Validate checks if the given value is valid or not.
+func (r AllRule) Validate(value interface{}) error {
+	return r.ValidateWithContext(nil, value)
+}
+
+// ValidateWithContext checks if the given value is valid or not.
+func (r AllRule) ValidateWithContext(ctx context.Context, value interface{}) error {
+	var errs ErrorList
+	for _, rule := range r.rules {
+		if s, ok := rule.(skipRule); ok && s.skips(ctx, value) {
+			break
+		}
+
+		var err error
+		if rc, ok := rule.(RuleWithContext); ok && ctx != nil {
+			err = rc.ValidateWithContext(ctx, value)
+		} else {
+			err = rule.Validate(value)
+		}
+		if err != nil {
+			if ie, ok := err.(InternalError); ok && ie.InternalError() != nil {
+				return err
+			}
+			errs = append(errs, err)
+			if stopOnFirstError(ctx) && !IsWarning(err) {
+				break
+			}
+		}
+	}
+	return errs.Filter()
+}
+
+// nestedRules returns the rules to evaluate.
+func (r AllRule) nestedRules() [][]Rule {
+	return [][]Rule{r.rules}
+}
+
+// withNestedRules returns a copy of the rule that evaluates the given rules instead.
+func (r AllRule) withNestedRules(rules [][]Rule) Rule {
+	r.rules = rules[0]
+	return r
+}
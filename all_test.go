@@ -0,0 +1,61 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAll(t *testing.T) {
+	digit := Match(regexp.MustCompile("[0-9]")).Error("must contain a digit")
+	internal := By(func(interface{}) error { return NewInternalError(errors.New("internal")) })
+	tests := []struct {
+		tag   string
+		value interface{}
+		rules []Rule
+		err   string
+	}{
+		{"t1", "abc", []Rule{}, ""},
+		{"t2", "abcdefgh1", []Rule{Length(8, 0), digit}, ""},
+		{"t3", "abcdefgh", []Rule{Length(8, 0), digit}, "must contain a digit"},
+		{"t4", "abc", []Rule{Length(8, 0), digit}, "the length must be no less than 8; must contain a digit"},
+		{"t5", "abc", []Rule{Length(8, 0), Skip, digit}, "the length must be no less than 8"},
+		{"t6", "abc", []Rule{Length(8, 0), Skip.When(false), digit}, "the length must be no less than 8; must contain a digit"},
+		{"t7", "abc", []Rule{Length(8, 0), internal, digit}, "internal"},
+		{"t8", "", []Rule{Required, digit}, "cannot be blank"},
+		{"t9", "abc", []Rule{All(Length(8, 0), In("x")), digit}, "the length must be no less than 8; must be a valid value; must contain a digit"},
+	}
+	for _, test := range tests {
+		r := All(test.rules...)
+		err := r.Validate(test.value)
+		assertError(t, test.err, err, test.tag)
+		err = r.ValidateWithContext(context.Background(), test.value)
+		assertError(t, test.err, err, test.tag)
+	}
+
+	err := Validate("abc", All(Length(8, 0), digit))
+	assert.Equal(t, ErrorList{ErrLengthTooShort.SetParams(map[string]interface{}{"min": 8, "max": 0}), digit.err}, err)
+	assert.True(t, errors.Is(err, ErrLengthTooShort))
+
+	err = ValidateWithContext(context.WithValue(context.Background(), contains, "abc"), "xyz", All(
+		WithContext(func(ctx context.Context, value interface{}) error {
+			if value != ctx.Value(contains) {
+				return errors.New("unexpected")
+			}
+			return nil
+		}),
+		Length(4, 0),
+	))
+	assertError(t, "unexpected; the length must be no less than 4", err, "t10")
+
+	m := Model1{A: "abc"}
+	err = ValidateStruct(&m, Field(&m.A, All(Length(8, 0), digit)))
+	assertError(t, "A: the length must be no less than 8; must contain a digit.", err, "t11")
+	assert.Equal(t, []FieldError{
+		{Path: "A", Code: "validation_length_too_short", Message: "the length must be no less than 8", Params: map[string]interface{}{"min": 8, "max": 0}},
+		{Path: "A", Code: "validation_match_invalid", Message: "must contain a digit"},
+	}, Flatten(err, PathDotted))
+}
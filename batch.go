@@ -0,0 +1,191 @@
+// Copyright 2016 Qiang Xue, 2022 Jellydator. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+type (
+	// BatchLoader validates many values at once, typically by looking them up in a remote store with a single query.
+	BatchLoader interface {
+		// Load validates the given distinct values and returns their validation errors, in the same order.
+		// The error of a valid value is nil. If the second result is not nil, the values cannot be validated
+		// and the error is reported as an InternalError.
+		Load(ctx context.Context, values []interface{}) ([]error, error)
+	}
+
+	// BatchLoaderFunc is a function that implements BatchLoader.
+	BatchLoaderFunc func(ctx context.Context, values []interface{}) ([]error, error)
+
+	// BatchRule is a validation rule that validates values with a BatchLoader.
+	BatchRule struct {
+		source *batchSource
+	}
+
+	// batchSource identifies the loader of a BatchRule, so that the values of a rule used in several places
+	// are loaded together.
+	batchSource struct {
+		loader BatchLoader
+	}
+
+	// batchState holds the values collected for each loader during ValidateBatched, and their errors.
+	batchState struct {
+		mu         sync.Mutex
+		collecting bool
+		batches    map[*batchSource]*batchValues
+	}
+
+	// batchValues holds the distinct values collected for a loader, and their errors once loaded.
+	batchValues struct {
+		values  []interface{}
+		indexes map[interface{}]int
+		errs    []error
+	}
+
+	batchStateKey struct{}
+)
+
+// Load calls f(ctx, values).
+func (f BatchLoaderFunc) Load(ctx context.Context, values []interface{}) ([]error, error) {
+	return f(ctx, values)
+}
+
+// NewBatchRule returns a validation rule that validates values with the given loader, such as a rule checking
+// that a username is not taken yet. The values must be comparable. An empty value is considered valid.
+//
+// When validating within ValidateBatched, the values of all the rules sharing the same BatchRule are collected
+// over the whole value being validated, e.g. every element of a slice or every field of a struct tree,
+// and the loader is called once with all of them. Otherwise, the loader is called once per value.
+func NewBatchRule(loader BatchLoader) BatchRule {
+	return BatchRule{source: &batchSource{loader: loader}}
+}
+
+// Validate checks if the given value is valid or not. The loader is called with the value only.
+func (r BatchRule) Validate(value interface{}) error {
+	return r.ValidateWithContext(nil, value)
+}
+
+// ValidateWithContext checks if the given value is valid or not.
+// Within ValidateBatched, the results of a single call of the loader are used.
+func (r BatchRule) ValidateWithContext(ctx context.Context, value interface{}) error {
+	value, isNil := Indirect(value)
+	if isNil || IsEmpty(value) {
+		return nil
+	}
+	// the dynamic value must be checked, as an interface field may hold an uncomparable value
+	if !reflect.ValueOf(value).Comparable() {
+		return fmt.Errorf("type not supported: %v", reflect.TypeOf(value))
+	}
+
+	if ctx != nil {
+		if state, ok := ctx.Value(batchStateKey{}).(*batchState); ok {
+			if ok, err := state.lookup(r.source, value); ok {
+				return err
+			}
+		}
+	}
+	return r.source.load(ctx, value)
+}
+
+// load validates a single value with the loader.
+func (s *batchSource) load(ctx context.Context, value interface{}) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	errs, err := s.loader.Load(ctx, []interface{}{value})
+	if err == nil && len(errs) != 1 {
+		err = fmt.Errorf("the loader returned %v errors for 1 value", len(errs))
+	}
+	if err != nil {
+		return NewInternalError(err)
+	}
+	return errs[0]
+}
+
+// lookup returns the error of the given value. While values are being collected, the value is recorded
+// and a nil error is returned. It returns false if the value was not collected.
+func (s *batchState) lookup(source *batchSource, value interface{}) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bv := s.batches[source]
+	if s.collecting {
+		if bv == nil {
+			bv = &batchValues{indexes: map[interface{}]int{}}
+			s.batches[source] = bv
+		}
+		if _, ok := bv.indexes[value]; !ok {
+			bv.indexes[value] = len(bv.values)
+			bv.values = append(bv.values, value)
+		}
+		return true, nil
+	}
+
+	if bv == nil {
+		return false, nil
+	}
+	i, ok := bv.indexes[value]
+	if !ok {
+		return false, nil
+	}
+	return true, bv.errs[i]
+}
+
+// ValidateBatched calls validate so that the BatchRules used during the validation call their loaders
+// once for all the values they validate. For example,
+//
+//	usernameAvailable := validation.NewBatchRule(loader)
+//	err := validation.ValidateBatched(ctx, func(ctx context.Context) error {
+//	    return validation.ValidateWithContext(ctx, usernames, validation.Each(usernameAvailable))
+//	})
+//
+// To do so, validate is called twice with a context derived from ctx: the first call collects the values
+// of the BatchRules and its result is ignored, unless it is an InternalError. The loaders are then called,
+// and the second call validates the values with their results. Therefore, the rules used by validate
+// should have no side effects. If a loader fails, an InternalError wrapping its error is returned.
+//
+// The values are only collected if the BatchRules are evaluated with the given context, e.g. by
+// ValidateWithContext, ValidateStructWithContext or the ValidateWithContext method of a ValidatableWithContext.
+// The other values are validated by calling the loader once per value.
+func ValidateBatched(ctx context.Context, validate func(ctx context.Context) error) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	state := &batchState{
+		collecting: true,
+		batches:    map[*batchSource]*batchValues{},
+	}
+	ctx = context.WithValue(ctx, batchStateKey{}, state)
+
+	// collect the values of every field and element, even if WithStopOnFirstError is used,
+	// without storing the warnings, which are found again by the second call
+	collectCtx := context.WithValue(ctx, stopOnFirstErrorKey{}, false)
+	err := validate(context.WithValue(collectCtx, warningsKey{}, warningsSink{}))
+	if ie, ok := err.(InternalError); ok && ie.InternalError() != nil {
+		return err
+	}
+
+	state.mu.Lock()
+	state.collecting = false
+	state.mu.Unlock()
+
+	for source, bv := range state.batches {
+		errs, err := source.loader.Load(ctx, bv.values)
+		if err == nil && len(errs) != len(bv.values) {
+			err = fmt.Errorf("the loader returned %v errors for %v values", len(errs), len(bv.values))
+		}
+		if err != nil {
+			return NewInternalError(err)
+		}
+		bv.errs = errs
+	}
+
+	return validate(ctx)
+}
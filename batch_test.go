@@ -0,0 +1,161 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errUsernameTaken = NewError("username_taken", "is already taken")
+
+// memoryLoader is an in-memory BatchLoader that reports the values found in a set, and records its calls.
+type memoryLoader struct {
+	mu    sync.Mutex
+	taken map[interface{}]bool
+	calls [][]interface{}
+	err   error
+}
+
+func (l *memoryLoader) Load(_ context.Context, values []interface{}) ([]error, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.calls = append(l.calls, values)
+	if l.err != nil {
+		return nil, l.err
+	}
+	errs := make([]error, len(values))
+	for i, value := range values {
+		if l.taken[value] {
+			errs[i] = errUsernameTaken
+		}
+	}
+	return errs, nil
+}
+
+type batchAccount struct {
+	Username string `json:"username"`
+	Backup   string `json:"backup"`
+}
+
+type batchTeam struct {
+	Owner   batchAccount
+	Members []batchAccount
+}
+
+func TestBatchRule(t *testing.T) {
+	loader := &memoryLoader{taken: map[interface{}]bool{"bob": true, "eve": true}}
+	rule := NewBatchRule(loader)
+
+	assert.NoError(t, Validate("alice", rule))
+	assert.Equal(t, errUsernameTaken, Validate("bob", rule))
+	assert.Equal(t, errUsernameTaken, ValidateWithContext(context.Background(), "bob", rule))
+	assert.NoError(t, Validate("", rule))
+	assert.Len(t, loader.calls, 3)
+
+	err := Validate([]string{"alice"}, rule)
+	assert.EqualError(t, err, "type not supported: []string")
+	err = ValidateBatched(context.Background(), func(ctx context.Context) error {
+		return ValidateWithContext(ctx, collectionAny{[]int{1}}, rule)
+	})
+	assert.EqualError(t, err, "type not supported: validation.collectionAny")
+
+	// without ValidateBatched, the loader is called for every value
+	loader.calls = nil
+	err = ValidateWithContext(context.Background(), []string{"alice", "bob", "carol", "eve"}, Each(rule))
+	assertError(t, "1: is already taken; 3: is already taken.", err, "t1.1")
+	assert.Len(t, loader.calls, 4)
+
+	// loader errors
+	failing := NewBatchRule(BatchLoaderFunc(func(context.Context, []interface{}) ([]error, error) {
+		return nil, errors.New("db is down")
+	}))
+	err = Validate("alice", failing)
+	if assert.Implements(t, (*InternalError)(nil), err) {
+		assert.EqualError(t, err, "db is down")
+	}
+	invalid := NewBatchRule(BatchLoaderFunc(func(context.Context, []interface{}) ([]error, error) {
+		return nil, nil
+	}))
+	err = Validate("alice", invalid)
+	assert.EqualError(t, err, "the loader returned 0 errors for 1 value")
+}
+
+func TestValidateBatched(t *testing.T) {
+	loader := &memoryLoader{taken: map[interface{}]bool{"bob": true, "eve": true}}
+	rule := NewBatchRule(loader)
+
+	usernames := make([]string, 500)
+	for i := range usernames {
+		usernames[i] = "user" + strconv.Itoa(i%250)
+	}
+	usernames[10], usernames[20] = "bob", "eve"
+
+	tests := []struct {
+		tag      string
+		validate func(ctx context.Context) error
+		err      string
+		values   int
+	}{
+		{"slice", func(ctx context.Context) error {
+			return ValidateWithContext(ctx, usernames, Each(Required, rule))
+		}, "10: is already taken; 20: is already taken.", 252},
+		{"parallel", func(ctx context.Context) error {
+			return ValidateWithContext(ctx, usernames, EachParallel(4, rule))
+		}, "10: is already taken; 20: is already taken.", 252},
+		{"stop on first error", func(ctx context.Context) error {
+			return ValidateWithContext(WithStopOnFirstError(ctx), usernames, Each(rule))
+		}, "10: is already taken.", 252},
+		{"struct tree", func(ctx context.Context) error {
+			team := batchTeam{
+				Owner:   batchAccount{Username: "alice", Backup: "bob"},
+				Members: []batchAccount{{Username: "eve"}, {Username: "carol", Backup: "alice"}},
+			}
+			accountRules := func(a *batchAccount) []*FieldRules {
+				return []*FieldRules{Field(&a.Username, Required, rule), Field(&a.Backup, rule)}
+			}
+			return ValidateStructWithContext(ctx, &team,
+				FieldStruct(&team.Owner, accountRules(&team.Owner)...),
+				Field(&team.Members, Each(WithContext(func(ctx context.Context, value interface{}) error {
+					a := value.(batchAccount)
+					return ValidateStructWithContext(ctx, &a, accountRules(&a)...)
+				}))),
+			)
+		}, "Members: (0: (username: is already taken.).); Owner: (backup: is already taken.).", 4},
+		{"valid", func(ctx context.Context) error {
+			return ValidateWithContext(ctx, []string{"alice", "carol"}, Each(rule))
+		}, "", 2},
+	}
+	for _, test := range tests {
+		loader.calls = nil
+		err := ValidateBatched(context.Background(), test.validate)
+		assertError(t, test.err, err, test.tag)
+		if assert.Len(t, loader.calls, 1, test.tag) {
+			assert.Len(t, loader.calls[0], test.values, test.tag)
+		}
+	}
+
+	// loader errors
+	loader.err = errors.New("db is down")
+	loader.calls = nil
+	err := ValidateBatched(nil, func(ctx context.Context) error {
+		return ValidateWithContext(ctx, usernames, Each(rule))
+	})
+	if assert.Implements(t, (*InternalError)(nil), err) {
+		assert.EqualError(t, err, "db is down")
+	}
+	assert.Len(t, loader.calls, 1)
+
+	// internal errors of the first pass
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	loader.calls = nil
+	err = ValidateBatched(ctx, func(ctx context.Context) error {
+		return ValidateWithContext(ctx, usernames, Each(rule))
+	})
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Len(t, loader.calls, 0)
+}
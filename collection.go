@@ -0,0 +1,288 @@
+// Copyright 2016 Qiang Xue, 2022 Jellydator. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+)
+
+var (
+	// ErrUnique is the error that returns for an element that duplicates a previous element of an iterable.
+	ErrUnique = NewError("validation_unique", "must be unique")
+	// ErrSorted is the error that returns for an element that is out of order.
+	ErrSorted = NewError("validation_sorted", "is out of order")
+	// ErrContains is the error that returns when no element of an iterable satisfies the given rules.
+	ErrContains = NewError("validation_contains", "must contain a matching element")
+	// ErrMinMatches is the error that returns when too few elements of an iterable satisfy the given rules.
+	ErrMinMatches = NewError("validation_min_matches", "must contain at least {{.min}} matching elements")
+)
+
+// UniqueRule is a validation rule that checks if the elements of an iterable are unique.
+type UniqueRule struct {
+	key func(elem interface{}) interface{}
+	err Error
+}
+
+// Unique returns a validation rule that checks if the elements of an iterable (map, slice or array) are unique.
+// Every element that equals a previous one is reported, keyed by its index (or its key for a map, whose elements
+// are visited in the order of their keys). The elements must be comparable. Nil and empty elements are ignored.
+// An empty iterable is considered valid.
+func Unique() UniqueRule {
+	return UniqueRule{err: ErrUnique}
+}
+
+// UniqueBy returns a validation rule that checks if the elements of an iterable have unique keys, as returned
+// by the given function. For example, it can check that the elements of a slice of structs have unique IDs.
+// The keys must be comparable. Please refer to Unique for more details.
+func UniqueBy(key func(elem interface{}) interface{}) UniqueRule {
+	return UniqueRule{key: key, err: ErrUnique}
+}
+
+// Error sets the error message for the rule.
+func (r UniqueRule) Error(message string) UniqueRule {
+	r.err = r.err.SetMessage(message)
+	return r
+}
+
+// ErrorObject sets the error struct for the rule.
+func (r UniqueRule) ErrorObject(err Error) UniqueRule {
+	r.err = err
+	return r
+}
+
+// Validate checks if the given value is valid or not.
+func (r UniqueRule) Validate(value interface{}) error {
+	keys, elems, err := iterableElements(value)
+	if err != nil || len(elems) == 0 {
+		return err
+	}
+
+	errs := Errors{}
+	seen := make(map[interface{}]string, len(elems))
+	for i, elem := range elems {
+		if elem == nil || IsEmpty(elem) {
+			continue
+		}
+		if r.key != nil {
+			elem = r.key(elem)
+		}
+		// the dynamic values must be checked, as an interface field may hold an uncomparable value
+		if elem != nil && !reflect.ValueOf(elem).Comparable() {
+			return fmt.Errorf("type not supported: %v", reflect.TypeOf(elem))
+		}
+		if _, ok := seen[elem]; ok {
+			errs[keys[i]] = r.err
+			continue
+		}
+		seen[elem] = keys[i]
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// SortedRule is a validation rule that checks if the elements of an iterable are sorted.
+type SortedRule struct {
+	cmp func(a, b interface{}) int
+	err Error
+}
+
+// Sorted returns a validation rule that checks if the elements of an iterable (map, slice or array) are sorted
+// in ascending order according to the given comparison function, which returns a negative number when a < b,
+// a positive number when a > b and zero otherwise. Every element that is less than the element preceding it
+// is reported, keyed by its index (or its key for a map, whose elements are visited in the order of their keys).
+//
+// If cmp is nil, the elements are compared by their natural order. Only int, uint, float, string and time.Time
+// elements are supported then. Pass a reversed comparison function to check for a descending order.
+// Nil elements are ignored. An empty iterable is considered valid.
+func Sorted(cmp func(a, b interface{}) int) SortedRule {
+	return SortedRule{cmp: cmp, err: ErrSorted}
+}
+
+// Error sets the error message for the rule.
+func (r SortedRule) Error(message string) SortedRule {
+	r.err = r.err.SetMessage(message)
+	return r
+}
+
+// ErrorObject sets the error struct for the rule.
+func (r SortedRule) ErrorObject(err Error) SortedRule {
+	r.err = err
+	return r
+}
+
+// Validate checks if the given value is valid or not.
+func (r SortedRule) Validate(value interface{}) error {
+	keys, elems, err := iterableElements(value)
+	if err != nil || len(elems) == 0 {
+		return err
+	}
+
+	errs := Errors{}
+	var prev interface{}
+	for i, elem := range elems {
+		if elem == nil {
+			continue
+		}
+		if prev != nil {
+			var c int
+			if r.cmp != nil {
+				c = r.cmp(prev, elem)
+			} else if c, err = compareValues(prev, elem); err != nil {
+				return err
+			}
+			if c > 0 {
+				errs[keys[i]] = r.err
+				// keep the greatest element so far as the reference
+				continue
+			}
+		}
+		prev = elem
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// MatchCountRule is a validation rule that checks if enough elements of an iterable satisfy the given rules.
+type MatchCountRule struct {
+	rules []Rule
+	min   int
+	err   Error
+}
+
+// Contains returns a validation rule that checks if at least one element of an iterable (map, slice or array)
+// satisfies all the given rules. For example, validation.Contains(validation.In("admin")).
+// An empty iterable is considered valid. Please use the Required rule to make sure the iterable is not empty.
+func Contains(rules ...Rule) MatchCountRule {
+	return MatchCountRule{rules: rules, min: 1, err: ErrContains}
+}
+
+// MinMatches returns a validation rule that checks if at least min elements of an iterable (map, slice or array)
+// satisfy all the given rules. Please refer to Contains for more details.
+func MinMatches(min int, rules ...Rule) MatchCountRule {
+	return MatchCountRule{rules: rules, min: min, err: ErrMinMatches}
+}
+
+// Error sets the error message for the rule.
+func (r MatchCountRule) Error(message string) MatchCountRule {
+	r.err = r.err.SetMessage(message)
+	return r
+}
+
+// ErrorObject sets the error struct for the rule.
+func (r MatchCountRule) ErrorObject(err Error) MatchCountRule {
+	r.err = err
+	return r
+}
+
+// Validate checks if the given value is valid or not.
+func (r MatchCountRule) Validate(value interface{}) error {
+	return r.ValidateWithContext(nil, value)
+}
+
+// ValidateWithContext checks if the given value is valid or not.
+func (r MatchCountRule) ValidateWithContext(ctx context.Context, value interface{}) error {
+	_, elems, err := iterableElements(value)
+	if err != nil || len(elems) == 0 || r.min <= 0 {
+		return err
+	}
+
+	matches := 0
+	for _, elem := range elems {
+		if err := contextError(ctx); err != nil {
+			return err
+		}
+		err = validateNested(ctx, elem, r.rules...)
+		if ie, ok := err.(InternalError); ok && ie.InternalError() != nil {
+			return err
+		}
+		if err == nil {
+			if matches++; matches >= r.min {
+				return nil
+			}
+		}
+	}
+
+	return r.err.SetParams(map[string]interface{}{"min": r.min})
+}
+
+// iterableElements returns the error keys and the elements of the given map, slice or array, as EachRule
+// names and validates them. The elements of a map are returned in the order of their keys.
+// A nil value has no elements.
+func iterableElements(value interface{}) ([]string, []interface{}, error) {
+	value, isNil := Indirect(value)
+	if isNil {
+		return nil, nil, nil
+	}
+
+	var each EachRule
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Map:
+		mapKeys := v.MapKeys()
+		sort.Slice(mapKeys, func(i, j int) bool {
+			c, err := compareValues(mapKeys[i].Interface(), mapKeys[j].Interface())
+			if err != nil {
+				return getErrorKeyName(mapKeys[i].Interface()) < getErrorKeyName(mapKeys[j].Interface())
+			}
+			return c < 0
+		})
+		keys := make([]string, len(mapKeys))
+		elems := make([]interface{}, len(mapKeys))
+		for i, k := range mapKeys {
+			keys[i] = each.getString(k)
+			elems[i] = each.getInterface(v.MapIndex(k))
+		}
+		return keys, elems, nil
+	case reflect.Slice, reflect.Array:
+		keys := make([]string, v.Len())
+		elems := make([]interface{}, v.Len())
+		for i := range elems {
+			keys[i] = strconv.Itoa(i)
+			elems[i] = each.getInterface(v.Index(i))
+		}
+		return keys, elems, nil
+	}
+	return nil, nil, errors.New("must be an iterable (map, slice or array)")
+}
+
+// compareValues compares two values of the same type by their natural order. It returns a negative number
+// when a < b, a positive number when a > b and zero otherwise. Only int, uint, float, string and time.Time
+// values are supported.
+func compareValues(a, b interface{}) (int, error) {
+	ta, tb := reflect.TypeOf(a), reflect.TypeOf(b)
+	if ta != tb {
+		return 0, fmt.Errorf("cannot compare %v with %v", ta, tb)
+	}
+
+	if t, ok := a.(time.Time); ok {
+		return t.Compare(b.(time.Time)), nil
+	}
+
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	switch va.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return cmp.Compare(va.Int(), vb.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return cmp.Compare(va.Uint(), vb.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return cmp.Compare(va.Float(), vb.Float()), nil
+	case reflect.String:
+		return cmp.Compare(va.String(), vb.String()), nil
+	}
+	return 0, fmt.Errorf("type not supported: %v", ta)
+}
@@ -0,0 +1,145 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type collectionItem struct {
+	ID   int
+	Name string
+}
+
+type collectionAny struct {
+	Value interface{}
+}
+
+func TestUnique(t *testing.T) {
+	a, b := "a", "a"
+	tests := []struct {
+		tag   string
+		rule  UniqueRule
+		value interface{}
+		err   string
+	}{
+		{"t1.1", Unique(), nil, ""},
+		{"t1.2", Unique(), []string{}, ""},
+		{"t1.3", Unique(), []string{"a", "b", "c"}, ""},
+		{"t1.4", Unique(), []string{"a", "b", "a", "c", "b", "a"}, "2: must be unique; 4: must be unique; 5: must be unique."},
+		{"t1.5", Unique(), [3]int{1, 2, 1}, "2: must be unique."},
+		{"t1.6", Unique(), &[]int{1, 1}, "1: must be unique."},
+		{"t1.7", Unique(), []string{"", "a", ""}, ""},
+		{"t1.8", Unique(), []*string{&a, nil, &b, nil}, "2: must be unique."},
+		{"t1.9", Unique(), map[int]string{3: "x", 1: "x", 2: "y", 10: "x"}, "10: must be unique; 3: must be unique."},
+		{"t1.10", Unique(), map[string]int{"b": 1, "a": 1}, "b: must be unique."},
+		{"t1.11", Unique(), [][]int{{1}, {1}}, "type not supported: []int"},
+		{"t1.12", Unique(), "abc", "must be an iterable (map, slice or array)"},
+		{"t1.13", Unique(), []collectionAny{{[]int{1}}, {[]int{1}}}, "type not supported: validation.collectionAny"},
+		{"t1.14", Unique(), []collectionAny{{1}, {"a"}, {1}}, "2: must be unique."},
+		// UniqueBy
+		{"t2.1", UniqueBy(func(elem interface{}) interface{} {
+			return elem.(collectionItem).ID
+		}), []collectionItem{{1, "a"}, {2, "a"}, {1, "b"}}, "2: must be unique."},
+		{"t2.2", UniqueBy(func(elem interface{}) interface{} {
+			return strings.ToLower(elem.(string))
+		}), []string{"a@example.com", "A@example.com"}, "1: must be unique."},
+		// custom error
+		{"t3.1", Unique().Error("is a duplicate"), []int{1, 1}, "1: is a duplicate."},
+		{"t3.2", Unique().ErrorObject(NewError("code", "dup")), []int{1, 1}, "1: dup."},
+	}
+
+	for _, test := range tests {
+		err := Validate(test.value, test.rule)
+		assertError(t, test.err, err, test.tag)
+	}
+}
+
+func TestSorted(t *testing.T) {
+	now := time.Now()
+	desc := func(a, b interface{}) int {
+		return b.(int) - a.(int)
+	}
+	tests := []struct {
+		tag   string
+		rule  SortedRule
+		value interface{}
+		err   string
+	}{
+		{"t1.1", Sorted(nil), nil, ""},
+		{"t1.2", Sorted(nil), []int{}, ""},
+		{"t1.3", Sorted(nil), []int{1, 2, 2, 3}, ""},
+		{"t1.4", Sorted(nil), []int{1, 3, 2, 4, 0}, "2: is out of order; 4: is out of order."},
+		{"t1.5", Sorted(nil), []uint{2, 1}, "1: is out of order."},
+		{"t1.6", Sorted(nil), []float64{1.5, 1.2}, "1: is out of order."},
+		{"t1.7", Sorted(nil), []string{"a", "c", "b"}, "2: is out of order."},
+		{"t1.8", Sorted(nil), []time.Time{now, now.Add(-time.Hour)}, "1: is out of order."},
+		{"t1.9", Sorted(nil), map[int]int{1: 10, 2: 30, 10: 20}, "10: is out of order."},
+		{"t1.10", Sorted(nil), []collectionItem{{}, {}}, "type not supported: validation.collectionItem"},
+		{"t1.11", Sorted(nil), []interface{}{1, "a"}, "cannot compare int with string"},
+		// custom comparison
+		{"t2.1", Sorted(desc), []int{3, 2, 2, 1}, ""},
+		{"t2.2", Sorted(desc), []int{3, 4, 1}, "1: is out of order."},
+		// custom error
+		{"t3.1", Sorted(nil).Error("must be ascending"), []int{2, 1}, "1: must be ascending."},
+		{"t3.2", Sorted(nil).ErrorObject(NewError("code", "unsorted")), []int{2, 1}, "1: unsorted."},
+	}
+
+	for _, test := range tests {
+		err := Validate(test.value, test.rule)
+		assertError(t, test.err, err, test.tag)
+	}
+}
+
+func TestMatchCountRule(t *testing.T) {
+	tests := []struct {
+		tag   string
+		rule  MatchCountRule
+		value interface{}
+		err   string
+	}{
+		{"t1.1", Contains(In("admin")), nil, ""},
+		{"t1.2", Contains(In("admin")), []string{}, ""},
+		{"t1.3", Contains(In("admin")), []string{"user", "admin"}, ""},
+		{"t1.4", Contains(In("admin")), []string{"user", "guest"}, "must contain a matching element"},
+		{"t1.5", Contains(In("admin")), map[string]string{"a": "admin"}, ""},
+		{"t1.6", Contains(In("admin")), "admin", "must be an iterable (map, slice or array)"},
+		// MinMatches
+		{"t2.1", MinMatches(2, Min(10)), []int{1, 10, 20}, ""},
+		{"t2.2", MinMatches(2, Min(10)), []int{1, 10, 2}, "must contain at least 2 matching elements"},
+		{"t2.3", MinMatches(0, Min(10)), []int{1}, ""},
+		// custom error
+		{"t3.1", Contains(In("admin")).Error("an admin is required"), []string{"user"}, "an admin is required"},
+		{"t3.2", MinMatches(2, Min(10)).ErrorObject(NewError("code", "too few {{.min}}")), []int{1}, "too few 2"},
+	}
+
+	for _, test := range tests {
+		err := Validate(test.value, test.rule)
+		assertError(t, test.err, err, test.tag)
+	}
+
+	// params
+	err := Validate([]int{1}, MinMatches(2, Min(10)))
+	assert.Equal(t, map[string]interface{}{"min": 2}, err.(Error).Params())
+
+	// context and internal errors
+	rule := Contains(WithContext(func(ctx context.Context, value interface{}) error {
+		if value != ctx.Value(contains) {
+			return errors.New("unexpected value")
+		}
+		return nil
+	}))
+	ctx := context.WithValue(context.Background(), contains, "abc")
+	assert.NoError(t, ValidateWithContext(ctx, []string{"xyz", "abc"}, rule))
+	assert.True(t, errors.Is(ValidateWithContext(ctx, []string{"xyz"}, rule), ErrContains))
+
+	internal := NewInternalError(errors.New("internal"))
+	err = Validate([]string{"abc"}, Contains(By(func(interface{}) error {
+		return internal
+	})))
+	assert.Equal(t, internal, err)
+}
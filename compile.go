@@ -0,0 +1,286 @@
+// Copyright 2016 Qiang Xue, 2022 Jellydator. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"context"
+	"reflect"
+	"unsafe"
+)
+
+type (
+	// StructValidator validates structs of type T with rules that have been resolved once by Compile.
+	// It is safe for concurrent use, provided that its rules are.
+	//
+	// A StructValidator is also a Rule (and a TypedRule[*T]) accepting a T or a *T, so it can be used
+	// with Each to validate a slice of structs, for example.
+	StructValidator[T any] struct {
+		plan *structPlan
+	}
+
+	// structPlan holds the resolved rules of a struct type.
+	structPlan struct {
+		typ    reflect.Type
+		fields []compiledField
+	}
+
+	// compiledField holds the resolved rules of a struct field, or those of a StructRule or Group.
+	compiledField struct {
+		// location is the location of the field; it is unused for the rules of a StructRule or Group.
+		location         fieldLocation
+		name             string
+		anonymous        bool
+		validatePtrValue bool
+		structKey        *string
+		rules            []Rule
+		// hasRefs tells if some of the rules, possibly nested, are structRules. Their field references
+		// are fieldLocations, which are turned into pointers to the fields of each struct being validated.
+		hasRefs bool
+	}
+
+	// fieldLocation locates a field by its offset within a struct.
+	fieldLocation struct {
+		offset uintptr
+		typ    reflect.Type
+	}
+)
+
+// Compile resolves the given field rules once and returns a StructValidator that can validate any struct
+// of type T with them. Unlike ValidateStruct, validating a struct with a StructValidator does not look up
+// the fields in the struct again, which makes it cheaper when the same rules are used many times.
+//
+// The fields are specified against proto, which is only used during the call, just like ValidateStruct
+// specifies them against the struct being validated. For example,
+//
+//	var p Person
+//	v, err := validation.Compile(&p,
+//	    validation.Field(&p.Name, validation.Required, validation.Length(5, 20)),
+//	    validation.Field(&p.Email, validation.Required, is.Email),
+//	)
+//	...
+//	err = v.ValidateStruct(&person)
+//
+// The rules must not capture proto otherwise: the field pointers given to the rules of this package, such as
+// EqualTo or ExactlyOneOf, are redirected to the struct being validated, including when these rules are nested
+// in When, All, Each or Warn, but those captured by closures are not.
+// The rules of StructRule receive a pointer to the struct being validated as their value.
+// Field names are resolved with GetErrorFieldName when Compile is called. Fields of structs embedded
+// by pointer are not supported.
+//
+// Compile returns the same errors as those ValidateStruct wraps into an InternalError when the fields are invalid.
+func Compile[T any](proto *T, fields ...*FieldRules) (*StructValidator[T], error) {
+	if proto == nil || reflect.TypeOf(proto).Elem().Kind() != reflect.Struct {
+		return nil, ErrStructPointer
+	}
+	plan, err := compileStruct(reflect.ValueOf(proto).Elem(), fields)
+	if err != nil {
+		return nil, err
+	}
+	return &StructValidator[T]{plan: plan}, nil
+}
+
+// MustCompile is like Compile but panics if the fields cannot be resolved.
+// It simplifies the initialization of package-level validators.
+func MustCompile[T any](proto *T, fields ...*FieldRules) *StructValidator[T] {
+	v, err := Compile(proto, fields...)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// ValidateStruct validates the given struct. A nil pointer is considered valid.
+func (v *StructValidator[T]) ValidateStruct(structPtr *T) error {
+	return v.ValidateStructWithContext(nil, structPtr)
+}
+
+// ValidateStructWithContext validates the given struct with the given context.
+// Please refer to ValidateStructWithContext for the supported context options.
+func (v *StructValidator[T]) ValidateStructWithContext(ctx context.Context, structPtr *T) error {
+	if structPtr == nil {
+		return nil
+	}
+	return reportWarnings(ctx, func(ctx context.Context) error {
+		return v.plan.validate(ctx, unsafe.Pointer(structPtr), structPtr)
+	})
+}
+
+// ValidateTyped validates the given struct. It is the same as ValidateStruct.
+func (v *StructValidator[T]) ValidateTyped(structPtr *T) error {
+	return v.ValidateStruct(structPtr)
+}
+
+// Validate validates the given value, which must be a T or a *T.
+func (v *StructValidator[T]) Validate(value interface{}) error {
+	return v.ValidateWithContext(nil, value)
+}
+
+// ValidateWithContext validates the given value, which must be a T or a *T, with the given context.
+func (v *StructValidator[T]) ValidateWithContext(ctx context.Context, value interface{}) error {
+	switch s := value.(type) {
+	case *T:
+		return v.ValidateStructWithContext(ctx, s)
+	case T:
+		return v.ValidateStructWithContext(ctx, &s)
+	}
+	return NewInternalError(ErrStructPointer)
+}
+
+// compileStruct resolves the given field rules against the given addressable struct.
+func compileStruct(structValue reflect.Value, fields []*FieldRules) (*structPlan, error) {
+	plan := &structPlan{
+		typ:    structValue.Type(),
+		fields: make([]compiledField, len(fields)),
+	}
+
+	for i, fr := range fields {
+		cf := compiledField{
+			validatePtrValue: fr.validatePtrValue,
+			structKey:        fr.structKey,
+		}
+		rules, ok := mapStructRules(fr.rules, func(sr structRule) (Rule, bool) {
+			bound, ok := sr.bindStruct(structValue)
+			if !ok {
+				return nil, false
+			}
+			fieldPtrs := sr.fieldRefs()
+			locations := make([]interface{}, len(fieldPtrs))
+			for k, fieldPtr := range fieldPtrs {
+				if locations[k], ok = locateField(structValue, reflect.ValueOf(fieldPtr)); !ok {
+					return nil, false
+				}
+			}
+			cf.hasRefs = true
+			return bound.(structRule).withFieldRefs(locations), true
+		})
+		if !ok {
+			return nil, ErrFieldRefNotFound(i)
+		}
+		cf.rules = rules
+
+		if fr.structKey == nil {
+			fv := reflect.ValueOf(fr.fieldPtr)
+			if fv.Kind() != reflect.Ptr {
+				return nil, ErrFieldPointer(i)
+			}
+			ft := findStructField(structValue, fv)
+			if ft == nil {
+				return nil, ErrFieldNotFound(i)
+			}
+			if cf.location, ok = locateField(structValue, fv); !ok {
+				return nil, ErrFieldNotFound(i)
+			}
+			cf.name = GetErrorFieldName(ft)
+			cf.anonymous = ft.Anonymous
+
+			if fr.fields != nil {
+				// replace the rule of FieldStruct with the compiled rules of the nested struct
+				if fv.Elem().Kind() != reflect.Struct {
+					return nil, ErrStructPointer
+				}
+				nested, err := compileStruct(fv.Elem(), fr.fields)
+				if err != nil {
+					return nil, err
+				}
+				cf.rules = []Rule{nested.rule()}
+				cf.hasRefs = false
+			}
+		}
+
+		plan.fields[i] = cf
+	}
+
+	return plan, nil
+}
+
+// locateField returns the location of the field the given pointer points to within the given addressable struct.
+// It returns false if the field does not lie within the memory of the struct.
+func locateField(structValue reflect.Value, fieldValue reflect.Value) (fieldLocation, bool) {
+	if fieldValue.Kind() != reflect.Ptr || fieldValue.IsNil() {
+		return fieldLocation{}, false
+	}
+	base, ptr := structValue.UnsafeAddr(), fieldValue.Pointer()
+	typ := fieldValue.Type().Elem()
+	if ptr < base || ptr+typ.Size() > base+structValue.Type().Size() {
+		return fieldLocation{}, false
+	}
+	return fieldLocation{offset: ptr - base, typ: typ}, true
+}
+
+// pointer returns a pointer to the field within the struct at the given address.
+func (l fieldLocation) pointer(base unsafe.Pointer) reflect.Value {
+	return reflect.NewAt(l.typ, unsafe.Add(base, l.offset))
+}
+
+// validate validates the struct at the given address. structPtr is the same struct, given to the rules
+// of StructRule and Group.
+func (p *structPlan) validate(ctx context.Context, base unsafe.Pointer, structPtr interface{}) error {
+	errs := Errors{}
+
+	for i := range p.fields {
+		if err := contextError(ctx); err != nil {
+			return err
+		}
+
+		cf := &p.fields[i]
+		rules := cf.bindRules(base)
+
+		var failed bool
+		var err error
+		if cf.structKey != nil {
+			failed, err = validateStructRules(ctx, errs, structPtr, rules, *cf.structKey)
+		} else {
+			fv := cf.location.pointer(base)
+			var validateValue interface{}
+			if !cf.validatePtrValue {
+				validateValue = fv.Elem().Interface()
+			} else {
+				validateValue = fv.Interface()
+			}
+			err = validateNested(ctx, validateValue, rules...)
+			failed, err = addFieldError(errs, err, cf.name, cf.anonymous)
+		}
+		if err != nil {
+			return err
+		}
+		if failed && stopOnFirstError(ctx) {
+			break
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// rule returns a rule that validates a pointer to a struct of the plan's type, as the rule of FieldStruct does.
+func (p *structPlan) rule() Rule {
+	return &inlineRule{
+		f: func(value interface{}) error {
+			return p.validate(nil, reflect.ValueOf(value).UnsafePointer(), value)
+		},
+		fc: func(ctx context.Context, value interface{}) error {
+			return p.validate(ctx, reflect.ValueOf(value).UnsafePointer(), value)
+		},
+	}
+}
+
+// bindRules returns the rules of the field, with the structRules among them, including nested ones,
+// referring to the fields of the struct at the given address.
+func (cf *compiledField) bindRules(base unsafe.Pointer) []Rule {
+	if !cf.hasRefs {
+		return cf.rules
+	}
+	rules, _ := mapStructRules(cf.rules, func(sr structRule) (Rule, bool) {
+		locations := sr.fieldRefs()
+		fieldPtrs := make([]interface{}, len(locations))
+		for i, l := range locations {
+			fieldPtrs[i] = l.(fieldLocation).pointer(base).Interface()
+		}
+		return sr.withFieldRefs(fieldPtrs), true
+	})
+	return rules
+}
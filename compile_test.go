@@ -0,0 +1,261 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type compiledAddress struct {
+	Street string
+	City   string `json:"city"`
+}
+
+type compiledUser struct {
+	compiledAddress
+	Name            string `json:"name"`
+	Password        string
+	PasswordConfirm string `json:"password_confirm"`
+	Email           string
+	Phone           string
+	Home            compiledAddress
+	Tags            []string
+}
+
+func compiledUserRules(u *compiledUser) []*FieldRules {
+	return []*FieldRules{
+		Field(&u.Name, Required, Length(2, 10)),
+		Field(&u.compiledAddress),
+		Field(&u.PasswordConfirm, EqualTo(&u.Password)),
+		Field(&u.Tags, Each(Required)),
+		FieldStruct(&u.Home, Field(&u.Home.City, Required)),
+		StructRule(ExactlyOneOf(&u.Email, &u.Phone)),
+	}
+}
+
+func (a compiledAddress) Validate() error {
+	return ValidateStruct(&a, Field(&a.Street, Required))
+}
+
+func TestCompile(t *testing.T) {
+	var proto compiledUser
+	v, err := Compile(&proto, compiledUserRules(&proto)...)
+	require.NoError(t, err)
+
+	tests := []struct {
+		tag   string
+		model compiledUser
+		err   string
+	}{
+		{"t1.1", compiledUser{
+			compiledAddress: compiledAddress{Street: "a"},
+			Name:            "john",
+			Email:           "j@example.com",
+			Home:            compiledAddress{Street: "b", City: "x"},
+		}, ""},
+		{"t1.2", compiledUser{}, "Home: (city: cannot be blank.); Street: cannot be blank; _struct: exactly one of Email, Phone must be set; name: cannot be blank."},
+		{"t1.3", compiledUser{
+			compiledAddress: compiledAddress{Street: "a"},
+			Name:            "j",
+			Password:        "abc",
+			PasswordConfirm: "abd",
+			Email:           "j@example.com",
+			Phone:           "123",
+			Home:            compiledAddress{Street: "b", City: "x"},
+			Tags:            []string{"a", ""},
+		}, "Email: cannot be set together with Phone; Phone: cannot be set together with Email; Tags: (1: cannot be blank.); _struct: exactly one of Email, Phone must be set; name: the length must be between 2 and 10; password_confirm: must be equal to Password."},
+	}
+	for _, test := range tests {
+		u := test.model
+		err := v.ValidateStruct(&u)
+		assertError(t, test.err, err, test.tag)
+
+		// the compiled rules give the same result as ValidateStruct
+		expected := ValidateStruct(&u, compiledUserRules(&u)...)
+		assert.Equal(t, expected, err, test.tag)
+	}
+
+	// nil pointer
+	assert.NoError(t, v.ValidateStruct(nil))
+
+	// as a rule
+	users := []compiledUser{tests[0].model, tests[1].model}
+	err = Validate(users, Each(v))
+	assertError(t, "1: (Home: (city: cannot be blank.); Street: cannot be blank; _struct: exactly one of Email, Phone must be set; name: cannot be blank.).", err, "t2.1")
+	err = Validate(&users[0], v)
+	assert.NoError(t, err)
+	err = Validate("abc", v)
+	assert.Equal(t, NewInternalError(ErrStructPointer), err)
+	err = ValidateValue(&users[1], TypedRule[*compiledUser](v))
+	assert.Error(t, err)
+}
+
+func TestCompile_NestedStructRules(t *testing.T) {
+	rules := func(u *compiledUser) []*FieldRules {
+		return []*FieldRules{
+			Field(&u.PasswordConfirm, When(true, EqualTo(&u.Password))),
+			Field(&u.Email, All(Length(0, 5), Warn(ExcludedWith(&u.Phone)))),
+			Field(&u.Tags, Each(When(false).Else(RequiredWith(&u.Name)))),
+		}
+	}
+	var proto compiledUser
+	v := MustCompile(&proto, rules(&proto)...)
+
+	tests := []struct {
+		tag      string
+		model    compiledUser
+		err      string
+		warnings string
+	}{
+		{"t1", compiledUser{Password: "abc", PasswordConfirm: "abc"}, "", ""},
+		{"t2", compiledUser{Password: "abc", PasswordConfirm: "xyz"}, "password_confirm: must be equal to Password.", ""},
+		{"t3", compiledUser{Email: "john@example.com", Phone: "123"}, "Email: the length must be no more than 5.", "Email: must be blank when Phone is present."},
+		{"t4", compiledUser{Name: "john", Tags: []string{"a", ""}}, "Tags: (1: cannot be blank when name is present.).", ""},
+	}
+	for _, test := range tests {
+		u := test.model
+		var warnings, expected error
+		err := v.ValidateStructWithContext(WithWarnings(context.Background(), &warnings), &u)
+		assertError(t, test.err, err, test.tag)
+		assertError(t, test.warnings, warnings, test.tag+" warnings")
+		assert.Equal(t, ValidateStructWithContext(WithWarnings(context.Background(), &expected), &u, rules(&u)...), err, test.tag)
+		assert.Equal(t, expected, warnings, test.tag)
+	}
+}
+
+func TestCompile_Error(t *testing.T) {
+	var u compiledUser
+	var other compiledUser
+
+	tests := []struct {
+		tag    string
+		fields []*FieldRules
+		err    error
+	}{
+		{"t1.1", []*FieldRules{Field(&u.Name), Field(u.Email)}, ErrFieldPointer(1)},
+		{"t1.2", []*FieldRules{Field(&other.Name)}, ErrFieldNotFound(0)},
+		{"t1.3", []*FieldRules{Field(&u.Name, EqualTo(&other.Name))}, ErrFieldRefNotFound(0)},
+		{"t1.4", []*FieldRules{FieldStruct(&u.Home, Field(&u.Name))}, ErrFieldNotFound(0)},
+		{"t1.5", []*FieldRules{Field(&u.Name), Field(&u.Email, When(true, Warn(EqualTo(&other.Name))))}, ErrFieldRefNotFound(1)},
+		{"t1.6", []*FieldRules{FieldStruct(&u.Name, Field(&u.Name))}, ErrStructPointer},
+	}
+	for _, test := range tests {
+		_, err := Compile(&u, test.fields...)
+		assert.Equal(t, test.err, err, test.tag)
+	}
+
+	_, err := Compile[compiledUser](nil)
+	assert.Equal(t, ErrStructPointer, err)
+	_, err = Compile(new(string))
+	assert.Equal(t, ErrStructPointer, err)
+
+	assert.Panics(t, func() {
+		MustCompile(&u, Field(&other.Name))
+	})
+}
+
+func TestStructValidator_ValidateStructWithContext(t *testing.T) {
+	var proto compiledUser
+	v := MustCompile(&proto,
+		Field(&proto.Name, WithContext(func(ctx context.Context, value interface{}) error {
+			if value != ctx.Value(contains) {
+				return errors.New("unexpected value")
+			}
+			return nil
+		})),
+		Field(&proto.Email, Required),
+		StructRule(By(func(value interface{}) error {
+			if value.(*compiledUser).Phone == "" {
+				return errors.New("phone is required")
+			}
+			return nil
+		})),
+	)
+
+	ctx := context.WithValue(context.Background(), contains, "abc")
+	u := compiledUser{compiledAddress: compiledAddress{Street: "a"}, Name: "abc"}
+	err := v.ValidateStructWithContext(ctx, &u)
+	assertError(t, "Email: cannot be blank; _struct: phone is required.", err, "t1.1")
+
+	err = v.ValidateStructWithContext(WithStopOnFirstError(ctx), &u)
+	assertError(t, "Email: cannot be blank.", err, "t1.2")
+
+	u.Name = "xyz"
+	err = v.ValidateStructWithContext(WithStopOnFirstError(ctx), &u)
+	assertError(t, "name: unexpected value.", err, "t1.3")
+}
+
+func TestStructValidator_Concurrent(t *testing.T) {
+	var proto compiledUser
+	v := MustCompile(&proto, compiledUserRules(&proto)...)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			u := compiledUser{
+				compiledAddress: compiledAddress{Street: "a"},
+				Name:            "john",
+				Password:        "abc",
+				PasswordConfirm: "abc",
+				Email:           "j@example.com",
+				Home:            compiledAddress{Street: "b", City: "x"},
+			}
+			if i%2 == 1 {
+				u.PasswordConfirm = "abd"
+			}
+			for j := 0; j < 100; j++ {
+				err := v.ValidateStruct(&u)
+				if i%2 == 1 {
+					assertError(t, "password_confirm: must be equal to Password.", err, "t1.1")
+				} else {
+					assert.NoError(t, err)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func BenchmarkStructValidator(b *testing.B) {
+	s := &benchStruct{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l", "m", "n", "o", "p"}
+
+	b.Run("ValidateStruct", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = s.validate(nil)
+		}
+	})
+
+	b.Run("Compiled", func(b *testing.B) {
+		var proto benchStruct
+		v := MustCompile(&proto,
+			Field(&proto.A, Required, Length(1, 10)),
+			Field(&proto.B, Required, Length(1, 10)),
+			Field(&proto.C, Required, Length(1, 10)),
+			Field(&proto.D, Required, Length(1, 10)),
+			Field(&proto.E, Required, Length(1, 10)),
+			Field(&proto.F, Required, Length(1, 10)),
+			Field(&proto.G, Required, Length(1, 10)),
+			Field(&proto.H, Required, Length(1, 10)),
+			Field(&proto.I, Required, Length(1, 10)),
+			Field(&proto.J, Required, Length(1, 10)),
+			Field(&proto.K, Required, Length(1, 10)),
+			Field(&proto.L, Required, Length(1, 10)),
+			Field(&proto.M, Required, Length(1, 10)),
+			Field(&proto.N, Required, Length(1, 10)),
+			Field(&proto.O, Required, Length(1, 10)),
+			Field(&proto.P, Required, Length(1, 10)),
+		)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_ = v.ValidateStruct(s)
+		}
+	})
+}
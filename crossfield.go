@@ -0,0 +1,350 @@
+// Copyright 2016 Qiang Xue, 2022 Jellydator. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+var (
+	// ErrEqualToField is the error that returns when a value is not equal to the value of another field.
+	ErrEqualToField = NewError("validation_equal_to_field", "must be equal to {{.field}}")
+	// ErrGreaterThanField is the error that returns when a value is not greater than the value of another field.
+	ErrGreaterThanField = NewError("validation_greater_than_field", "must be greater than {{.field}}")
+	// ErrLessThanField is the error that returns when a value is not less than the value of another field.
+	ErrLessThanField = NewError("validation_less_than_field", "must be less than {{.field}}")
+	// ErrRequiredIf is the error that returns when a value is empty while another field has a given value.
+	ErrRequiredIf = NewError("validation_required_if", "cannot be blank when {{.field}} is {{.value}}")
+	// ErrRequiredWith is the error that returns when a value is empty while another field is not.
+	ErrRequiredWith = NewError("validation_required_with", "cannot be blank when {{.field}} is present")
+	// ErrExcludedWith is the error that returns when a value is not empty while another field is not empty either.
+	ErrExcludedWith = NewError("validation_excluded_with", "must be blank when {{.field}} is present")
+)
+
+// ErrFieldRefNotFound is the error that a field referred to by a rule, such as EqualTo or ExactlyOneOf,
+// cannot be found in the struct.
+type ErrFieldRefNotFound int
+
+// Error returns the error string of ErrFieldRefNotFound.
+func (e ErrFieldRefNotFound) Error() string {
+	return fmt.Sprintf("field #%v refers to a field that cannot be found in the struct", int(e))
+}
+
+type crossFieldKind int
+
+const (
+	equalToField crossFieldKind = iota
+	greaterThanField
+	lessThanField
+	requiredIf
+	requiredWith
+	excludedWith
+)
+
+// CrossFieldRule is a validation rule that checks a struct field against other fields of the same struct.
+//
+// The other fields are specified as pointers, just like in Field, and their values are read when the rule
+// is evaluated. When the rule is used with ValidateStruct, the error params include the name of the other
+// field as "field", as returned by GetErrorFieldName, and its value as "value". The name is resolved whether
+// the rule is passed to Field directly or through When, All, Each or Warn. Otherwise, "another field" is used.
+type CrossFieldRule struct {
+	kind      crossFieldKind
+	fieldPtrs []interface{}
+	names     []string
+	values    []interface{}
+	err       Error
+}
+
+// EqualTo returns a validation rule that checks if a value is equal to the value of another struct field.
+// For example, validation.Field(&s.PasswordConfirm, validation.EqualTo(&s.Password)).
+// An empty value is considered valid. Please use the Required rule to make sure a value is not empty.
+func EqualTo(fieldPtr interface{}) CrossFieldRule {
+	return CrossFieldRule{kind: equalToField, fieldPtrs: []interface{}{fieldPtr}, err: ErrEqualToField}
+}
+
+// GreaterThanField returns a validation rule that checks if a value is strictly greater than the value
+// of another struct field. For example, validation.Field(&s.End, validation.GreaterThanField(&s.Start)).
+// The same types as those of Min are supported, and the values of both fields must be of the same type.
+// The rule is not checked if either value is empty.
+func GreaterThanField(fieldPtr interface{}) CrossFieldRule {
+	return CrossFieldRule{kind: greaterThanField, fieldPtrs: []interface{}{fieldPtr}, err: ErrGreaterThanField}
+}
+
+// LessThanField returns a validation rule that checks if a value is strictly less than the value
+// of another struct field. Please refer to GreaterThanField for more details.
+func LessThanField(fieldPtr interface{}) CrossFieldRule {
+	return CrossFieldRule{kind: lessThanField, fieldPtrs: []interface{}{fieldPtr}, err: ErrLessThanField}
+}
+
+// RequiredIf returns a validation rule that checks if a value is not empty when another struct field
+// is equal to one of the given values. For example, validation.Field(&s.VAT, validation.RequiredIf(&s.Kind, "company")).
+func RequiredIf(fieldPtr interface{}, values ...interface{}) CrossFieldRule {
+	return CrossFieldRule{kind: requiredIf, fieldPtrs: []interface{}{fieldPtr}, values: values, err: ErrRequiredIf}
+}
+
+// RequiredWith returns a validation rule that checks if a value is not empty when any of the given
+// struct fields is not empty.
+func RequiredWith(fieldPtrs ...interface{}) CrossFieldRule {
+	return CrossFieldRule{kind: requiredWith, fieldPtrs: fieldPtrs, err: ErrRequiredWith}
+}
+
+// ExcludedWith returns a validation rule that checks if a value is empty when any of the given
+// struct fields is not empty.
+func ExcludedWith(fieldPtrs ...interface{}) CrossFieldRule {
+	return CrossFieldRule{kind: excludedWith, fieldPtrs: fieldPtrs, err: ErrExcludedWith}
+}
+
+// Error sets the error message for the rule.
+func (r CrossFieldRule) Error(message string) CrossFieldRule {
+	r.err = r.err.SetMessage(message)
+	return r
+}
+
+// ErrorObject sets the error struct for the rule.
+func (r CrossFieldRule) ErrorObject(err Error) CrossFieldRule {
+	r.err = err
+	return r
+}
+
+// Validate checks if the given value is valid or not.
+func (r CrossFieldRule) Validate(value interface{}) error {
+	value, isNil := Indirect(value)
+	empty := isNil || IsEmpty(value)
+
+	switch r.kind {
+	case equalToField, greaterThanField, lessThanField:
+		other, ok := r.fieldValue(0)
+		if empty || !ok {
+			return nil
+		}
+		valid, err := r.compare(value, other)
+		if err != nil || valid {
+			return err
+		}
+		return r.error(0, other)
+
+	case requiredIf:
+		other, _ := r.fieldValue(0)
+		if !empty {
+			return nil
+		}
+		for _, v := range r.values {
+			if equalValues(other, v) {
+				return r.error(0, other)
+			}
+		}
+
+	case requiredWith, excludedWith:
+		if empty == (r.kind == excludedWith) {
+			return nil
+		}
+		for i := range r.fieldPtrs {
+			if other, ok := r.fieldValue(i); ok {
+				return r.error(i, other)
+			}
+		}
+	}
+	return nil
+}
+
+// bindStruct returns a copy of the rule that knows the names of the other fields of the given struct.
+func (r CrossFieldRule) bindStruct(structValue reflect.Value) (Rule, bool) {
+	names, ok := fieldNames(structValue, r.fieldPtrs)
+	if !ok {
+		return nil, false
+	}
+	r.names = names
+	return r, true
+}
+
+// fieldRefs returns the pointers to the other fields.
+func (r CrossFieldRule) fieldRefs() []interface{} {
+	return r.fieldPtrs
+}
+
+// withFieldRefs returns a copy of the rule that refers to the given fields instead.
+func (r CrossFieldRule) withFieldRefs(fieldPtrs []interface{}) Rule {
+	r.fieldPtrs = fieldPtrs
+	return r
+}
+
+// fieldValue returns the value of the i-th other field, and whether it is not empty.
+func (r CrossFieldRule) fieldValue(i int) (interface{}, bool) {
+	return referencedValue(r.fieldPtrs[i])
+}
+
+// compare compares the value with the value of the other field using the logic of ThresholdRule.
+func (r CrossFieldRule) compare(value, other interface{}) (bool, error) {
+	if r.kind == equalToField {
+		return equalValues(value, other), nil
+	}
+	rule := Min(other).Exclusive()
+	if r.kind == lessThanField {
+		rule = Max(other).Exclusive()
+	}
+	err := rule.Validate(value)
+	if _, ok := err.(Error); ok {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (r CrossFieldRule) error(i int, other interface{}) error {
+	name := "another field"
+	if i < len(r.names) {
+		name = r.names[i]
+	}
+	return r.err.SetParams(map[string]interface{}{"field": name, "value": other})
+}
+
+// equalValues checks if two values are equal, comparing time.Time values by the instant they represent.
+// Values of different types, such as a named string type and a string, or an int8 and an int, are compared
+// after converting b to the type of a if both are of the same kind or numbers, and the conversion is lossless.
+func equalValues(a, b interface{}) bool {
+	if ta, ok := a.(time.Time); ok {
+		if tb, ok := b.(time.Time); ok {
+			return ta.Equal(tb)
+		}
+	}
+	if reflect.DeepEqual(a, b) {
+		return true
+	}
+
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if !va.IsValid() || !vb.IsValid() || va.Type() == vb.Type() || !vb.Type().ConvertibleTo(va.Type()) {
+		return false
+	}
+	if va.Kind() != vb.Kind() && !(isNumberKind(va.Kind()) && isNumberKind(vb.Kind())) {
+		return false
+	}
+	converted := vb.Convert(va.Type())
+	if !reflect.DeepEqual(converted.Convert(vb.Type()).Interface(), b) {
+		// b is out of the range of the type of a
+		return false
+	}
+	return reflect.DeepEqual(a, converted.Interface())
+}
+
+// isNumberKind checks if the given kind is that of an integer or a floating-point number.
+func isNumberKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// referencedValue returns the indirect value of the field the given pointer points to,
+// and whether it is neither nil nor empty.
+func referencedValue(fieldPtr interface{}) (interface{}, bool) {
+	fv := reflect.ValueOf(fieldPtr)
+	if fv.Kind() != reflect.Ptr || fv.IsNil() {
+		return nil, false
+	}
+	value, isNil := Indirect(fv.Elem().Interface())
+	return value, !isNil && !IsEmpty(value)
+}
+
+// fieldNames returns the error names of the given fields of a struct.
+// It returns false if any of the fields cannot be found in the struct.
+func fieldNames(structValue reflect.Value, fieldPtrs []interface{}) ([]string, bool) {
+	names := make([]string, len(fieldPtrs))
+	for i, fieldPtr := range fieldPtrs {
+		fv := reflect.ValueOf(fieldPtr)
+		if fv.Kind() != reflect.Ptr {
+			return nil, false
+		}
+		ft := findStructField(structValue, fv)
+		if ft == nil {
+			return nil, false
+		}
+		names[i] = GetErrorFieldName(ft)
+	}
+	return names, true
+}
+
+// structRule is implemented by the rules that refer to other fields of the struct being validated.
+// ValidateStructWithContext binds such rules to the struct before evaluating them, while Compile
+// binds them once and makes them refer to the fields of each struct being validated.
+type structRule interface {
+	bindStruct(structValue reflect.Value) (Rule, bool)
+	// fieldRefs returns the pointers to the fields the rule refers to.
+	fieldRefs() []interface{}
+	// withFieldRefs returns a copy of the rule that refers to the given fields instead.
+	withFieldRefs(fieldPtrs []interface{}) Rule
+}
+
+// ruleContainer is implemented by the rules that apply other rules, such as When or Each,
+// so that the structRules among those are bound as well.
+type ruleContainer interface {
+	// nestedRules returns the lists of rules the rule applies.
+	nestedRules() [][]Rule
+	// withNestedRules returns a copy of the rule that applies the given lists of rules instead.
+	withNestedRules(rules [][]Rule) Rule
+}
+
+// bindStructRules binds the structRules among the given rules, including nested ones, to the given struct.
+// The rules are returned as is if none of them is a structRule.
+func bindStructRules(structValue reflect.Value, rules []Rule) ([]Rule, bool) {
+	return mapStructRules(rules, func(sr structRule) (Rule, bool) {
+		return sr.bindStruct(structValue)
+	})
+}
+
+// mapStructRules returns a copy of the given rules where the structRules, including those nested in
+// ruleContainers, are replaced with the results of f. The rules are returned as is if none of them is
+// a structRule. It returns false as soon as f does.
+func mapStructRules(rules []Rule, f func(sr structRule) (Rule, bool)) ([]Rule, bool) {
+	mapped, ok := replaceStructRules(rules, f)
+	if mapped == nil && ok {
+		return rules, true
+	}
+	return mapped, ok
+}
+
+// replaceStructRules is like mapStructRules, except that it returns nil if none of the rules is a structRule.
+func replaceStructRules(rules []Rule, f func(sr structRule) (Rule, bool)) ([]Rule, bool) {
+	var mapped []Rule
+	for i, rule := range rules {
+		var replacement Rule
+		switch rule := rule.(type) {
+		case structRule:
+			var ok bool
+			if replacement, ok = f(rule); !ok {
+				return nil, false
+			}
+		case ruleContainer:
+			nested := rule.nestedRules()
+			var changed bool
+			for j, rules := range nested {
+				m, ok := replaceStructRules(rules, f)
+				if !ok {
+					return nil, false
+				}
+				if m != nil {
+					nested[j] = m
+					changed = true
+				}
+			}
+			if !changed {
+				continue
+			}
+			replacement = rule.withNestedRules(nested)
+		default:
+			continue
+		}
+
+		if mapped == nil {
+			mapped = make([]Rule, len(rules))
+			copy(mapped, rules)
+		}
+		mapped[i] = replacement
+	}
+	return mapped, true
+}
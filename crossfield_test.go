@@ -0,0 +1,195 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type crossFieldModel struct {
+	Password        string
+	PasswordConfirm string `json:"password_confirm"`
+	Start           time.Time
+	End             time.Time
+	Min             int
+	Max             *int
+	Kind            string `json:"kind"`
+	VAT             string
+	Email           string
+	Phone           string
+}
+
+func TestCrossFieldRule(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	one, five := 1, 5
+	var m crossFieldModel
+	tests := []struct {
+		tag   string
+		model crossFieldModel
+		field func(m *crossFieldModel) *FieldRules
+		err   string
+	}{
+		// EqualTo
+		{"t1.1", crossFieldModel{Password: "abc", PasswordConfirm: "abc"}, func(m *crossFieldModel) *FieldRules {
+			return Field(&m.PasswordConfirm, EqualTo(&m.Password))
+		}, ""},
+		{"t1.2", crossFieldModel{Password: "abc", PasswordConfirm: "abd"}, func(m *crossFieldModel) *FieldRules {
+			return Field(&m.PasswordConfirm, EqualTo(&m.Password))
+		}, "password_confirm: must be equal to Password."},
+		{"t1.3", crossFieldModel{Password: "abc"}, func(m *crossFieldModel) *FieldRules {
+			return Field(&m.PasswordConfirm, EqualTo(&m.Password))
+		}, ""},
+		{"t1.4", crossFieldModel{Start: now, End: now.In(time.FixedZone("X", 3600))}, func(m *crossFieldModel) *FieldRules {
+			return Field(&m.End, EqualTo(&m.Start))
+		}, ""},
+		// GreaterThanField, LessThanField
+		{"t2.1", crossFieldModel{Start: now, End: now.Add(time.Hour)}, func(m *crossFieldModel) *FieldRules {
+			return Field(&m.End, GreaterThanField(&m.Start))
+		}, ""},
+		{"t2.2", crossFieldModel{Start: now, End: now}, func(m *crossFieldModel) *FieldRules {
+			return Field(&m.End, GreaterThanField(&m.Start))
+		}, "End: must be greater than Start."},
+		{"t2.3", crossFieldModel{End: now}, func(m *crossFieldModel) *FieldRules {
+			return Field(&m.End, GreaterThanField(&m.Start))
+		}, ""},
+		{"t2.4", crossFieldModel{Min: 1, Max: &five}, func(m *crossFieldModel) *FieldRules {
+			return Field(&m.Min, LessThanField(&m.Max))
+		}, ""},
+		{"t2.5", crossFieldModel{Min: 5, Max: &one}, func(m *crossFieldModel) *FieldRules {
+			return Field(&m.Min, LessThanField(&m.Max))
+		}, "Min: must be less than Max."},
+		{"t2.6", crossFieldModel{Min: 5, Max: &one}, func(m *crossFieldModel) *FieldRules {
+			return Field(&m.Max, GreaterThanField(&m.Min))
+		}, "Max: must be greater than Min."},
+		{"t2.7", crossFieldModel{Min: 5}, func(m *crossFieldModel) *FieldRules {
+			return Field(&m.Min, LessThanField(&m.Max))
+		}, ""},
+		{"t2.8", crossFieldModel{Min: 5, Kind: "a"}, func(m *crossFieldModel) *FieldRules {
+			return Field(&m.Min, LessThanField(&m.Kind))
+		}, "Min: type not supported: string."},
+		// RequiredIf
+		{"t3.1", crossFieldModel{Kind: "company", VAT: "123"}, func(m *crossFieldModel) *FieldRules {
+			return Field(&m.VAT, RequiredIf(&m.Kind, "company"))
+		}, ""},
+		{"t3.2", crossFieldModel{Kind: "company"}, func(m *crossFieldModel) *FieldRules {
+			return Field(&m.VAT, RequiredIf(&m.Kind, "company", "ngo"))
+		}, "VAT: cannot be blank when kind is company."},
+		{"t3.3", crossFieldModel{Kind: "person"}, func(m *crossFieldModel) *FieldRules {
+			return Field(&m.VAT, RequiredIf(&m.Kind, "company"))
+		}, ""},
+		// RequiredWith, ExcludedWith
+		{"t4.1", crossFieldModel{}, func(m *crossFieldModel) *FieldRules {
+			return Field(&m.Email, RequiredWith(&m.Phone, &m.Kind))
+		}, ""},
+		{"t4.2", crossFieldModel{Kind: "a"}, func(m *crossFieldModel) *FieldRules {
+			return Field(&m.Email, RequiredWith(&m.Phone, &m.Kind))
+		}, "Email: cannot be blank when kind is present."},
+		{"t4.3", crossFieldModel{Kind: "a", Email: "x"}, func(m *crossFieldModel) *FieldRules {
+			return Field(&m.Email, RequiredWith(&m.Phone, &m.Kind))
+		}, ""},
+		{"t4.4", crossFieldModel{Email: "x"}, func(m *crossFieldModel) *FieldRules {
+			return Field(&m.Email, ExcludedWith(&m.Phone))
+		}, ""},
+		{"t4.5", crossFieldModel{Email: "x", Phone: "1"}, func(m *crossFieldModel) *FieldRules {
+			return Field(&m.Email, ExcludedWith(&m.Phone))
+		}, "Email: must be blank when Phone is present."},
+		{"t4.6", crossFieldModel{Phone: "1"}, func(m *crossFieldModel) *FieldRules {
+			return Field(&m.Email, ExcludedWith(&m.Phone))
+		}, ""},
+		// custom error
+		{"t5.1", crossFieldModel{Password: "abc", PasswordConfirm: "abd"}, func(m *crossFieldModel) *FieldRules {
+			return Field(&m.PasswordConfirm, EqualTo(&m.Password).Error("must match {{.field}}"))
+		}, "password_confirm: must match Password."},
+		{"t5.2", crossFieldModel{Password: "abc", PasswordConfirm: "abd"}, func(m *crossFieldModel) *FieldRules {
+			return Field(&m.PasswordConfirm, EqualTo(&m.Password).ErrorObject(NewError("code", "mismatch")))
+		}, "password_confirm: mismatch."},
+		// invalid field references
+		{"t6.1", crossFieldModel{}, func(m *crossFieldModel) *FieldRules {
+			return Field(&m.PasswordConfirm, EqualTo(&m.Password), RequiredWith(&m.Phone, &crossFieldModel{}))
+		}, "field #0 refers to a field that cannot be found in the struct"},
+		{"t6.2", crossFieldModel{}, func(m *crossFieldModel) *FieldRules {
+			return Field(&m.PasswordConfirm, EqualTo(m.Password))
+		}, "field #0 refers to a field that cannot be found in the struct"},
+		{"t6.3", crossFieldModel{}, func(m *crossFieldModel) *FieldRules {
+			return Field(&m.PasswordConfirm, When(true, All(RequiredWith(&crossFieldModel{}))))
+		}, "field #0 refers to a field that cannot be found in the struct"},
+		// nested rules
+		{"t7.1", crossFieldModel{Password: "abc", PasswordConfirm: "abd"}, func(m *crossFieldModel) *FieldRules {
+			return Field(&m.PasswordConfirm, When(true, EqualTo(&m.Password)))
+		}, "password_confirm: must be equal to Password."},
+		{"t7.2", crossFieldModel{Password: "abc", PasswordConfirm: "abd"}, func(m *crossFieldModel) *FieldRules {
+			return Field(&m.PasswordConfirm, When(false).Else(All(Length(5, 0), EqualTo(&m.Password))))
+		}, "password_confirm: the length must be no less than 5; must be equal to Password."},
+	}
+	for _, test := range tests {
+		m = test.model
+		err := ValidateStruct(&m, test.field(&m))
+		assertError(t, test.err, err, test.tag)
+	}
+
+	// warnings
+	var warnings error
+	m = crossFieldModel{Email: "x", Phone: "1"}
+	err := ValidateStructWithContext(WithWarnings(context.Background(), &warnings), &m, Field(&m.Email, Warn(ExcludedWith(&m.Phone))))
+	assert.NoError(t, err)
+	assertError(t, "Email: must be blank when Phone is present.", warnings, "t7.3")
+
+	// params
+	m = crossFieldModel{Kind: "company"}
+	err = ValidateStruct(&m, Field(&m.VAT, RequiredIf(&m.Kind, "company")))
+	var e Error
+	if assert.True(t, errors.As(err.(Errors)["VAT"], &e)) {
+		assert.Equal(t, "validation_required_if", e.Code())
+		assert.Equal(t, map[string]interface{}{"field": "kind", "value": "company"}, e.Params())
+	}
+
+	// outside of a struct, the name of the other field is unknown
+	err = Validate("abd", EqualTo(&m.Kind))
+	assert.Equal(t, ErrEqualToField.SetParams(map[string]interface{}{"field": "another field", "value": "company"}), err)
+	assert.EqualError(t, err, "must be equal to another field")
+}
+
+type customerKind string
+
+func TestCrossFieldRule_NamedTypes(t *testing.T) {
+	m := struct {
+		Kind     customerKind `json:"kind"`
+		VAT      string
+		Confirm  string
+		Priority int8
+		Level    int8
+	}{Kind: "company", Confirm: "company", Priority: 2}
+
+	err := ValidateStruct(&m,
+		Field(&m.VAT, RequiredIf(&m.Kind, "company")),
+		Field(&m.Confirm, EqualTo(&m.Kind)),
+		Field(&m.Level, RequiredIf(&m.Priority, 2)),
+	)
+	assertError(t, "Level: cannot be blank when Priority is 2; VAT: cannot be blank when kind is company.", err, "t1")
+
+	m.Priority = 2
+	err = ValidateStruct(&m, Field(&m.Level, RequiredIf(&m.Priority, 258, 2.5)))
+	assert.NoError(t, err, "t2")
+
+	// values of other kinds are not converted
+	assert.False(t, equalValues(65, "A"))
+	assert.False(t, equalValues("A", 65))
+	assert.False(t, equalValues(customerKind("a"), nil))
+}
+
+func TestCrossFieldRule_Each(t *testing.T) {
+	m := struct {
+		Limit int    `json:"limit"`
+		Items []int  `json:"items"`
+		Range [2]int `json:"range"`
+	}{Limit: 3, Items: []int{1, 5}, Range: [2]int{1, 2}}
+
+	err := ValidateStruct(&m,
+		Field(&m.Items, Each(LessThanField(&m.Limit))),
+		Field(&m.Range, Each().At(1, GreaterThanField(&m.Limit))),
+	)
+	assertError(t, "items: (1: must be less than limit.); range: (1: must be greater than limit.).", err, "t1")
+}
@@ -8,6 +8,7 @@ import (
 	"context"
 	"errors"
 	"reflect"
+	"sort"
 	"strconv"
 )
 
@@ -20,9 +21,53 @@ func Each(rules ...Rule) EachRule {
 	}
 }
 
+// EachParallel is like Each, but validates the values concurrently using at most the given number of goroutines.
+// If workers is zero or negative, runtime.GOMAXPROCS(0) goroutines are used. The rules must be safe for concurrent use.
+//
+// The returned errors are the same as those of Each. If the validation context is canceled, the validation stops
+// and an InternalError wrapping ctx.Err() is returned.
+func EachParallel(workers int, rules ...Rule) EachRule {
+	return EachRule{
+		rules:   rules,
+		workers: normalizeWorkers(workers),
+	}
+}
+
 // EachRule is a validation rule that validates elements in a map/slice/array using the specified list of rules.
 type EachRule struct {
 	rules []Rule
+	// keys holds the rules validating the keys of a map.
+	keys []Rule
+	// positions holds the rules validating the elements of a slice or array at given indexes.
+	positions map[int][]Rule
+	// workers is the number of goroutines validating the elements; zero means the elements are validated sequentially.
+	workers int
+}
+
+// Keys specifies rules for the keys of a map. A key is validated before its value, and the value is only
+// validated if the key is valid. The rules are ignored for a slice or an array.
+func (r EachRule) Keys(rules ...Rule) EachRule {
+	r.keys = rules
+	return r
+}
+
+// At specifies additional rules for the element of a slice or an array at the given index, which is useful
+// for tuple-like arrays. For example,
+//
+//	validation.Each(validation.Required).
+//	    At(0, is.Latitude).
+//	    At(1, is.Longitude)
+//
+// The element is validated with the rules of Each first, then with the given ones. Calling At again with the
+// same index replaces its rules. The rules are ignored if the iterable has no element at the index, or is a map.
+func (r EachRule) At(index int, rules ...Rule) EachRule {
+	positions := make(map[int][]Rule, len(r.positions)+1)
+	for i, rules := range r.positions {
+		positions[i] = rules
+	}
+	positions[index] = rules
+	r.positions = positions
+	return r
 }
 
 // Validate loops through the given iterable and calls the Ozzo Validate() method for each value.
@@ -32,34 +77,47 @@ func (r EachRule) Validate(value interface{}) error {
 
 // ValidateWithContext loops through the given iterable and calls the Ozzo ValidateWithContext() method for each value.
 func (r EachRule) ValidateWithContext(ctx context.Context, value interface{}) error {
+	if r.workers > 1 {
+		return r.validateParallel(ctx, value)
+	}
+
 	errs := Errors{}
 
 	v := reflect.ValueOf(value)
 	switch v.Kind() {
 	case reflect.Map:
 		for _, k := range v.MapKeys() {
-			val := r.getInterface(v.MapIndex(k))
-			var err error
-			if ctx == nil {
-				err = Validate(val, r.rules...)
-			} else {
-				err = ValidateWithContext(ctx, val, r.rules...)
+			if err := contextError(ctx); err != nil {
+				return err
+			}
+			err := r.validateKey(ctx, k.Interface())
+			if err == nil {
+				err = validateNested(ctx, r.getInterface(v.MapIndex(k)), r.rules...)
 			}
 			if err != nil {
+				if ie, ok := err.(InternalError); ok && ie.InternalError() != nil {
+					return err
+				}
 				errs[r.getString(k)] = err
+				if stopOnFirstError(ctx) && !IsWarning(err) {
+					return errs
+				}
 			}
 		}
 	case reflect.Slice, reflect.Array:
 		for i := 0; i < v.Len(); i++ {
-			val := r.getInterface(v.Index(i))
-			var err error
-			if ctx == nil {
-				err = Validate(val, r.rules...)
-			} else {
-				err = ValidateWithContext(ctx, val, r.rules...)
+			if err := contextError(ctx); err != nil {
+				return err
 			}
+			err := validateNested(ctx, r.getInterface(v.Index(i)), r.elementRules(i)...)
 			if err != nil {
+				if ie, ok := err.(InternalError); ok && ie.InternalError() != nil {
+					return err
+				}
 				errs[strconv.Itoa(i)] = err
+				if stopOnFirstError(ctx) && !IsWarning(err) {
+					return errs
+				}
 			}
 		}
 	default:
@@ -72,6 +130,92 @@ func (r EachRule) ValidateWithContext(ctx context.Context, value interface{}) er
 	return nil
 }
 
+// validateParallel validates the elements of the given iterable concurrently.
+func (r EachRule) validateParallel(ctx context.Context, value interface{}) error {
+	var keys []string
+	var values []interface{}
+	var validate func(i int) error
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Map:
+		mapKeys := v.MapKeys()
+		for _, k := range mapKeys {
+			keys = append(keys, r.getString(k))
+			values = append(values, r.getInterface(v.MapIndex(k)))
+		}
+		validate = func(i int) error {
+			if err := r.validateKey(ctx, mapKeys[i].Interface()); err != nil {
+				return err
+			}
+			return validateNested(ctx, values[i], r.rules...)
+		}
+	case reflect.Slice, reflect.Array:
+		keys = make([]string, v.Len())
+		values = make([]interface{}, v.Len())
+		for i := range values {
+			keys[i] = strconv.Itoa(i)
+			values[i] = r.getInterface(v.Index(i))
+		}
+		validate = func(i int) error {
+			return validateNested(ctx, values[i], r.elementRules(i)...)
+		}
+	default:
+		return errors.New("must be an iterable (map, slice or array)")
+	}
+
+	return validateParallel(ctx, r.workers, keys, validate)
+}
+
+// validateKey validates the given map key with the key rules, if any.
+func (r EachRule) validateKey(ctx context.Context, key interface{}) error {
+	if len(r.keys) == 0 {
+		return nil
+	}
+	return validateNested(ctx, key, r.keys...)
+}
+
+// elementRules returns the rules validating the element of a slice or an array at the given index.
+func (r EachRule) elementRules(index int) []Rule {
+	rules, ok := r.positions[index]
+	if !ok {
+		return r.rules
+	}
+	return append(append(make([]Rule, 0, len(r.rules)+len(rules)), r.rules...), rules...)
+}
+
+// nestedRules returns the rules of the elements, those of the keys, then those of the positions by ascending index.
+func (r EachRule) nestedRules() [][]Rule {
+	rules := [][]Rule{r.rules, r.keys}
+	for _, index := range r.positionIndexes() {
+		rules = append(rules, r.positions[index])
+	}
+	return rules
+}
+
+// withNestedRules returns a copy of the rule with the given rules, in the order returned by nestedRules.
+func (r EachRule) withNestedRules(rules [][]Rule) Rule {
+	indexes := r.positionIndexes()
+	r.rules, r.keys = rules[0], rules[1]
+	if len(indexes) > 0 {
+		r.positions = make(map[int][]Rule, len(indexes))
+		for i, index := range indexes {
+			r.positions[index] = rules[i+2]
+		}
+	}
+	return r
+}
+
+// positionIndexes returns the indexes that have rules of their own, in ascending order.
+func (r EachRule) positionIndexes() []int {
+	indexes := make([]int, 0, len(r.positions))
+	for index := range r.positions {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+	return indexes
+}
+
 func (r EachRule) getInterface(value reflect.Value) interface{} {
 	switch value.Kind() {
 	case reflect.Ptr, reflect.Interface:
@@ -84,14 +228,12 @@ func (r EachRule) getInterface(value reflect.Value) interface{} {
 	}
 }
 
+// getString returns the name that should be used to represent the validation error of a map key.
+// It is formatted as MapRule formats keys, after dereferencing pointers. A nil key is represented by "".
 func (r EachRule) getString(value reflect.Value) string {
-	switch value.Kind() {
-	case reflect.Ptr, reflect.Interface:
-		if value.IsNil() {
-			return ""
-		}
-		return value.Elem().String()
-	default:
-		return value.String()
+	key := r.getInterface(value)
+	if key == nil {
+		return ""
 	}
+	return getErrorKeyName(key)
 }
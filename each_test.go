@@ -3,8 +3,12 @@ package validation
 import (
 	"context"
 	"errors"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestEach(t *testing.T) {
@@ -41,6 +45,95 @@ func TestEach(t *testing.T) {
 	}
 }
 
+func TestEachKeyNames(t *testing.T) {
+	one := 1
+	type point struct{ X, Y int }
+
+	tests := []struct {
+		tag   string
+		value interface{}
+		err   string
+	}{
+		{"t1", map[int]string{1: "", 20: ""}, "1: cannot be blank; 20: cannot be blank."},
+		{"t2", map[interface{}]string{1: "", true: ""}, "1: cannot be blank; true: cannot be blank."},
+		{"t3", map[*int]string{&one: ""}, "1: cannot be blank."},
+		{"t4", map[point]string{{1, 2}: ""}, "{1 2}: cannot be blank."},
+		{"t5", map[float64]string{1.5: ""}, "1.5: cannot be blank."},
+	}
+
+	for _, test := range tests {
+		err := Validate(test.value, Each(Required))
+		assertError(t, test.err, err, test.tag)
+		err = Validate(test.value, EachParallel(2, Required))
+		assertError(t, test.err, err, test.tag+" parallel")
+	}
+}
+
+func TestEachRule_Keys(t *testing.T) {
+	rule := Each(Required).Keys(Min(1), Max(10))
+
+	tests := []struct {
+		tag   string
+		value interface{}
+		err   string
+	}{
+		{"t1", map[int]string{1: "a", 10: "b"}, ""},
+		{"t2", map[int]string{-1: "a", 5: "", 11: ""}, "-1: must be no less than 1; 11: must be no greater than 10; 5: cannot be blank."},
+		{"t3", []string{"a", ""}, "1: cannot be blank."},
+	}
+
+	for _, test := range tests {
+		err := Validate(test.value, rule)
+		assertError(t, test.err, err, test.tag)
+		err = ValidateWithContext(context.Background(), test.value, rule)
+		assertError(t, test.err, err, test.tag+" ctx")
+		err = Validate(test.value, EachParallel(2, Required).Keys(Min(1), Max(10)))
+		assertError(t, test.err, err, test.tag+" parallel")
+	}
+
+	// key rules with context
+	ctxRule := Each().Keys(WithContext(func(ctx context.Context, value interface{}) error {
+		if value != ctx.Value(contains) {
+			return errors.New("unexpected key")
+		}
+		return nil
+	}))
+	ctx := context.WithValue(context.Background(), contains, "abc")
+	err := ValidateWithContext(ctx, map[string]string{"abc": "", "xyz": ""}, ctxRule)
+	assertError(t, "xyz: unexpected key.", err, "t4")
+}
+
+func TestEachRule_At(t *testing.T) {
+	base := Each(Required).At(0, Min(-90.0), Max(90.0)).At(1, Min(-180.0), Max(180.0))
+
+	tests := []struct {
+		tag   string
+		rule  EachRule
+		value interface{}
+		err   string
+	}{
+		{"t1", base, [2]float64{45, 120}, ""},
+		{"t2", base, [2]float64{100, 120}, "0: must be no greater than 90."},
+		{"t3", base, []float64{45, -200, 0}, "1: must be no less than -180; 2: cannot be blank."},
+		{"t4", base, []float64{45}, ""},
+		{"t5", base, map[int]float64{0: 100}, ""},
+		{"t6", base.At(0, Max(10.0)), [2]float64{45, 120}, "0: must be no greater than 10."},
+		{"t7", Each().At(1, Required), []string{"", ""}, "1: cannot be blank."},
+	}
+
+	for _, test := range tests {
+		err := Validate(test.value, test.rule)
+		assertError(t, test.err, err, test.tag)
+		test.rule.workers = 2
+		err = Validate(test.value, test.rule)
+		assertError(t, test.err, err, test.tag+" parallel")
+	}
+
+	// At does not modify the rule it is called on
+	err := Validate([2]float64{45, 120}, base)
+	assert.NoError(t, err)
+}
+
 func TestEachWithContext(t *testing.T) {
 	rule := Each(WithContext(func(ctx context.Context, value interface{}) error {
 		if !strings.Contains(value.(string), ctx.Value(contains).(string)) {
@@ -72,3 +165,66 @@ func TestEachWithContext(t *testing.T) {
 		assertError(t, test.err, err, test.tag)
 	}
 }
+
+func TestEachParallel(t *testing.T) {
+	values := make([]string, 1000)
+	m := map[string]string{}
+	for i := range values {
+		if i%7 != 3 {
+			values[i] = "abc"
+		}
+		m[strconv.Itoa(i)] = values[i]
+	}
+
+	for _, value := range []interface{}{values, m, &values} {
+		for _, ctx := range []context.Context{nil, context.Background(), WithStopOnFirstError(context.Background())} {
+			var expected, err error
+			if ctx == nil {
+				expected = Validate(value, Each(Required))
+				err = Validate(value, EachParallel(4, Required))
+			} else {
+				expected = ValidateWithContext(ctx, value, Each(Required))
+				err = ValidateWithContext(ctx, value, EachParallel(0, Required))
+			}
+			if _, ok := value.(map[string]string); ok && stopOnFirstError(ctx) {
+				// the first failing key of a map is not deterministic
+				assert.Len(t, err, 1)
+				continue
+			}
+			assert.Equal(t, expected, err)
+		}
+	}
+
+	assert.NoError(t, Validate([]string{}, EachParallel(4, Required)))
+	assertError(t, "must be an iterable (map, slice or array)", Validate(0, EachParallel(4, Required)), "t1.1")
+
+	// cancellation
+	ctx, cancel := context.WithCancel(context.Background())
+	rule := EachParallel(2, By(func(interface{}) error {
+		cancel()
+		return nil
+	}))
+	err := ValidateWithContext(ctx, values, rule)
+	if assert.Implements(t, (*InternalError)(nil), err) {
+		assert.Equal(t, context.Canceled, err.(InternalError).InternalError())
+	}
+
+	// the elements following an internal error are not validated
+	internal := NewInternalError(errors.New("db is down"))
+	var calls atomic.Int64
+	err = Validate(values, EachParallel(2, By(func(interface{}) error {
+		if calls.Add(1) == 10 {
+			return internal
+		}
+		return nil
+	})))
+	assert.Equal(t, internal, err)
+	assert.Less(t, calls.Load(), int64(20))
+
+	// panics are propagated
+	assert.PanicsWithValue(t, "oops", func() {
+		_ = Validate(values, EachParallel(4, By(func(interface{}) error {
+			panic("oops")
+		})))
+	})
+}
@@ -10,6 +10,8 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"text/template"
 )
 
@@ -27,15 +29,19 @@ type (
 	// ErrorObject is the default validation error
 	// that implements the Error interface.
 	ErrorObject struct {
-		code    string
-		message string
-		params  map[string]interface{}
+		code     string
+		message  string
+		params   map[string]interface{}
+		severity Severity
 	}
 
 	// Errors represents the validation errors that are indexed by struct field names, map or slice keys.
 	// values are Error or Errors (for map, slice and array error value is Errors).
 	Errors map[string]error
 
+	// ErrorList represents multiple validation errors of a single value, such as the ones returned by All.
+	ErrorList []error
+
 	// InternalError represents an error that should NOT be treated as a validation error.
 	InternalError interface {
 		error
@@ -45,8 +51,32 @@ type (
 	internalError struct {
 		error
 	}
+
+	// codeError is the target of errors.Is returned by ByCode.
+	codeError struct {
+		err Error
+	}
 )
 
+// ByCode returns a target for errors.Is that matches the validation errors having the same code as the given
+// error, whatever their messages. This way, errors whose messages have been translated or overridden, e.g. with
+// the Error method of a rule, still match the sentinel errors. Params are compared as ErrorObject.Is does.
+// For example,
+//
+//	if errors.Is(err, validation.ByCode(validation.ErrRequired)) {
+//	    // ...
+//	}
+//
+// An error without code is never matched.
+func ByCode(err Error) error {
+	return codeError{err: err}
+}
+
+// Error returns the error message of the matched error.
+func (e codeError) Error() string {
+	return e.err.Error()
+}
+
 // NewInternalError wraps a given error into an InternalError.
 func NewInternalError(err error) InternalError {
 	return internalError{error: err}
@@ -57,6 +87,11 @@ func (e internalError) InternalError() error {
 	return e.error
 }
 
+// Unwrap returns the actual error that it wraps around, so that errors.Is and errors.As can inspect it.
+func (e internalError) Unwrap() error {
+	return e.error
+}
+
 // SetCode set the error's translation code.
 func (e ErrorObject) SetCode(code string) Error {
 	e.code = code
@@ -89,6 +124,17 @@ func (e ErrorObject) Params() map[string]interface{} {
 	return e.params
 }
 
+// SetSeverity set the error's severity.
+func (e ErrorObject) SetSeverity(severity Severity) Error {
+	e.severity = severity
+	return e
+}
+
+// Severity returns the error's severity, which is SeverityError unless it is set otherwise, e.g. by Warn.
+func (e ErrorObject) Severity() Severity {
+	return e.severity
+}
+
 // SetMessage set the error's message.
 func (e ErrorObject) SetMessage(message string) Error {
 	e.message = message
@@ -100,39 +146,108 @@ func (e ErrorObject) Message() string {
 	return e.message
 }
 
-// Error returns the error message.
+// Error returns the error message, i.e. the message template rendered with the params.
+// If the template is invalid or cannot be rendered, the message is returned as is.
 func (e ErrorObject) Error() string {
-	if len(e.params) == 0 {
+	if len(e.params) == 0 || !strings.Contains(e.message, "{{") {
+		// the message has no action to render
 		return e.message
 	}
 
+	t, err := messageTemplate(e.message)
+	if err != nil {
+		return e.message
+	}
 	res := bytes.Buffer{}
-	_ = template.Must(template.New("err").Parse(e.message)).Execute(&res, e.params)
+	if err := t.Execute(&res, e.params); err != nil {
+		return e.message
+	}
 
 	return res.String()
 }
 
+// maxMessageTemplates is the maximum number of compiled message templates that are cached.
+const maxMessageTemplates = 1024
+
+// messageTemplateEntry holds a compiled message template, or the error found when compiling it.
+type messageTemplateEntry struct {
+	t   *template.Template
+	err error
+}
+
+var (
+	// messageTemplates caches the compiled message templates by message.
+	messageTemplates sync.Map
+	// messageTemplatesLen is the number of entries in messageTemplates.
+	messageTemplatesLen atomic.Int64
+)
+
+// messageTemplate returns the compiled template of the given message. The templates are cached, so that the
+// messages of the built-in errors are compiled once. The cache is cleared once it is full, so that one-off
+// messages, such as those of errors decoded from JSON, do not keep the other messages out of it for good.
+func messageTemplate(message string) (*template.Template, error) {
+	if entry, ok := messageTemplates.Load(message); ok {
+		return entry.(*messageTemplateEntry).t, entry.(*messageTemplateEntry).err
+	}
+
+	t, err := template.New("err").Parse(message)
+	if _, loaded := messageTemplates.LoadOrStore(message, &messageTemplateEntry{t: t, err: err}); !loaded {
+		if messageTemplatesLen.Add(1) > maxMessageTemplates {
+			clearMessageTemplates()
+		}
+	}
+	return t, err
+}
+
+// clearMessageTemplates removes all the entries of the message template cache.
+func clearMessageTemplates() {
+	messageTemplates.Range(func(key, _ interface{}) bool {
+		if _, loaded := messageTemplates.LoadAndDelete(key); loaded {
+			messageTemplatesLen.Add(-1)
+		}
+		return true
+	})
+}
+
 // Is checks if this error matches the supplied error.
 // If err is not an ErrorObject, it always returns false.
-// It returns true if Code() and Message() are the same.
+// It returns true if Code() and Message() are the same, or if Code() is the same and the message of err
+// renders as Error() with the params of this error, as for an error decoded from JSON.
 // If err.Params() is non-nil, we also check that all the params match.
 // This way, we can check an error against a sentinel error, such as ErrLengthTooLong.
+// If err was returned by ByCode, the messages are not compared.
 func (e ErrorObject) Is(err error) bool {
+	if ce, ok := err.(codeError); ok {
+		return e.code != "" && e.code == ce.err.Code() && e.matchParams(ce.err.Params())
+	}
+
 	eo, ok := err.(ErrorObject)
 	if !ok {
 		return false
 	}
 
-	if e.message != eo.message || e.code != eo.code {
+	if e.code != eo.code {
 		return false
 	}
+	if e.message != eo.message {
+		eo.params = e.params
+		if eo.Error() != e.Error() {
+			return false
+		}
+		eo = err.(ErrorObject)
+	}
+
+	return e.matchParams(eo.params)
+}
 
-	if len(eo.params) == 0 {
+// matchParams checks if the params of the error match the given ones. Empty params match any params.
+func (e ErrorObject) matchParams(params map[string]interface{}) bool {
+	if len(params) == 0 {
 		return true
 	}
 
 	for k, v := range e.params {
-		if eo.params[k] != v {
+		if params[k] != v {
 			return false
 		}
 	}
@@ -180,9 +295,14 @@ func (es Errors) Unwrap() []error {
 }
 
 // MarshalJSON converts the Errors into a valid JSON.
+// As they do not make a value invalid, warnings are omitted, including those of nested Errors and ErrorLists.
+// Please use MarshalErrorJSON with JSONStructured to include the codes, params and severities of the errors.
 func (es Errors) MarshalJSON() ([]byte, error) {
 	errs := map[string]interface{}{}
 	for key, err := range es {
+		if IsWarning(err) {
+			continue
+		}
 		if ms, ok := err.(json.Marshaler); ok {
 			errs[key] = ms
 		} else {
@@ -206,6 +326,76 @@ func (es Errors) Filter() error {
 	return es
 }
 
+// FilterSeverity removes all nils and the errors of other severities than the given ones from Errors,
+// and returns back the updated Errors as an error. The errors found within nested Errors and ErrorLists
+// are removed as well, the nested errors being copied rather than modified. For example,
+// FilterSeverity(SeverityError) removes the warnings. If the length of Errors becomes 0, it will return nil.
+func (es Errors) FilterSeverity(severities ...Severity) error {
+	for key, value := range es {
+		if value = filterSeverity(value, severities...); value != nil {
+			es[key] = value
+		} else {
+			delete(es, key)
+		}
+	}
+	if len(es) == 0 {
+		return nil
+	}
+	return es
+}
+
+// Error returns the error messages of the ErrorList, separated by semicolons.
+func (el ErrorList) Error() string {
+	var s strings.Builder
+	for i, err := range el {
+		if i > 0 {
+			s.WriteString("; ")
+		}
+		s.WriteString(err.Error())
+	}
+	return s.String()
+}
+
+// Unwrap returns the errors in the ErrorList.
+func (el ErrorList) Unwrap() []error {
+	return el
+}
+
+// MarshalJSON converts the ErrorList into a JSON array of error messages.
+// As for Errors, warnings are omitted.
+func (el ErrorList) MarshalJSON() ([]byte, error) {
+	errs := make([]interface{}, 0, len(el))
+	for _, err := range el {
+		if IsWarning(err) {
+			continue
+		}
+		if ms, ok := err.(json.Marshaler); ok {
+			errs = append(errs, ms)
+		} else {
+			errs = append(errs, err.Error())
+		}
+	}
+	return json.Marshal(errs)
+}
+
+// Filter removes all nils from the ErrorList. It returns nil if the ErrorList becomes empty,
+// the only remaining error if there is just one, and the updated ErrorList otherwise.
+func (el ErrorList) Filter() error {
+	errs := el[:0]
+	for _, err := range el {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	}
+	return errs
+}
+
 // NewError create new validation error.
 func NewError(code, message string) Error {
 	return ErrorObject{
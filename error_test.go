@@ -5,7 +5,9 @@
 package validation
 
 import (
+	"encoding/json"
 	"errors"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -83,6 +85,22 @@ func TestErrors_Unwrap(t *testing.T) {
 	assert.Len(t, unwrapped, 2)
 }
 
+func TestErrorList(t *testing.T) {
+	el := ErrorList{ErrRequired, nil, Errors{"0": errors.New("A1")}}
+	assert.Nil(t, ErrorList{}.Filter())
+	assert.Nil(t, ErrorList{nil}.Filter())
+	assert.Equal(t, ErrRequired, ErrorList{nil, ErrRequired}.Filter())
+
+	err := el.Filter()
+	assert.Equal(t, ErrorList{ErrRequired, Errors{"0": errors.New("A1")}}, err)
+	assert.EqualError(t, err, "cannot be blank; 0: A1.")
+	assert.True(t, errors.Is(err, ErrRequired))
+
+	data, e := json.Marshal(Errors{"A": err})
+	assert.Nil(t, e)
+	assert.Equal(t, `{"A":["cannot be blank",{"0":"A1"}]}`, string(data))
+}
+
 func TestErrorObject_SetCode(t *testing.T) {
 	err := NewError("A", "msg").(ErrorObject)
 
@@ -117,6 +135,93 @@ func TestErrorObject_Message(t *testing.T) {
 	assert.Equal(t, err.Message(), "A")
 }
 
+func TestErrorObject_Error(t *testing.T) {
+	clearMessageTemplates()
+	defer clearMessageTemplates()
+
+	tests := []struct {
+		tag     string
+		message string
+		params  map[string]interface{}
+		err     string
+	}{
+		{"t1", "abc", nil, "abc"},
+		{"t2", "{{.min}}", nil, "{{.min}}"},
+		{"t3", "must be at least {{.min}}", map[string]interface{}{"min": 3}, "must be at least 3"},
+		{"t4", "{{.missing}}", map[string]interface{}{"min": 3}, "<no value>"},
+		// invalid templates
+		{"t5", "must be {{.min", map[string]interface{}{"min": 3}, "must be {{.min"},
+		{"t6", "{{template \"x\"}}", map[string]interface{}{"min": 3}, "{{template \"x\"}}"},
+		{"t7", "{{.min.x}}", map[string]interface{}{"min": 3}, "{{.min.x}}"},
+	}
+
+	for _, test := range tests {
+		err := NewError("code", test.message).SetParams(test.params)
+		assert.Equal(t, test.err, err.Error(), test.tag)
+		// the cached template gives the same result
+		assert.Equal(t, test.err, err.Error(), test.tag)
+	}
+
+	// messages without actions are not compiled
+	_, cached := messageTemplates.Load("abc")
+	assert.False(t, cached)
+	assert.Equal(t, "x", NewError("code", "x").SetParams(map[string]interface{}{"min": 3}).Error())
+	_, cached = messageTemplates.Load("x")
+	assert.False(t, cached)
+
+	// the cache is cleared once it is full
+	clearMessageTemplates()
+	for i := 0; i < maxMessageTemplates+10; i++ {
+		err := NewError("code", "{{.n}} "+strconv.Itoa(i)).SetParams(map[string]interface{}{"n": i})
+		assert.Equal(t, strconv.Itoa(i)+" "+strconv.Itoa(i), err.Error())
+		assert.LessOrEqual(t, messageTemplatesLen.Load(), int64(maxMessageTemplates))
+	}
+	assert.Equal(t, int64(9), messageTemplatesLen.Load())
+
+	// the messages used after clearing the cache are cached again
+	err := ErrLengthOutOfRange.SetParams(map[string]interface{}{"min": 5, "max": 10})
+	assert.Equal(t, "the length must be between 5 and 10", err.Error())
+	_, cached = messageTemplates.Load(ErrLengthOutOfRange.Message())
+	assert.True(t, cached)
+}
+
+func BenchmarkErrorObject_Error(b *testing.B) {
+	err := ErrLengthOutOfRange.SetParams(map[string]interface{}{"min": 5, "max": 10})
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = err.Error()
+	}
+}
+
+func BenchmarkErrors_Error(b *testing.B) {
+	// a tree of 10 structs of 10 slices of 10 elements
+	errs := Errors{}
+	for i := 0; i < 10; i++ {
+		s := Errors{}
+		for j := 0; j < 10; j++ {
+			elems := Errors{}
+			for k := 0; k < 10; k++ {
+				elems[strconv.Itoa(k)] = ErrLengthOutOfRange.SetParams(map[string]interface{}{"min": k, "max": k + 10})
+			}
+			s["field"+strconv.Itoa(j)] = elems
+		}
+		errs["struct"+strconv.Itoa(i)] = s
+	}
+
+	b.Run("Error", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = errs.Error()
+		}
+	})
+	b.Run("MarshalJSON", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = errs.MarshalJSON()
+		}
+	})
+}
+
 func TestErrorObject_Params(t *testing.T) {
 	p := map[string]interface{}{"A": "val1", "AA": "val2"}
 
@@ -153,6 +258,25 @@ func TestErrorObject_Is(t *testing.T) {
 	assert.True(t, err.Is(err5))
 }
 
+func TestByCode(t *testing.T) {
+	translated := ErrRequired.SetMessage("darf nicht leer sein")
+	overridden := ErrLengthOutOfRange.SetMessage("too long").SetParams(map[string]interface{}{"min": 1, "max": 5})
+	codeless := NewError("", "abc")
+
+	assert.False(t, errors.Is(translated, ErrRequired))
+	assert.False(t, errors.Is(overridden, ErrLengthOutOfRange))
+
+	assert.True(t, errors.Is(translated, ByCode(ErrRequired)))
+	assert.True(t, errors.Is(Errors{"name": translated}, ByCode(ErrRequired)))
+	assert.True(t, errors.Is(overridden, ByCode(ErrLengthOutOfRange)))
+	assert.True(t, errors.Is(overridden, ByCode(ErrLengthOutOfRange.SetParams(map[string]interface{}{"min": 1, "max": 5}))))
+	assert.False(t, errors.Is(overridden, ByCode(ErrLengthOutOfRange.SetParams(map[string]interface{}{"min": 2, "max": 5}))))
+	assert.False(t, errors.Is(translated, ByCode(ErrNil)))
+	assert.False(t, errors.Is(codeless, ByCode(NewError("", "abc"))))
+	assert.True(t, errors.Is(codeless, NewError("", "abc")))
+	assert.Equal(t, "cannot be blank", ByCode(ErrRequired).Error())
+}
+
 func TestErrorObject_AddParam2(t *testing.T) {
 	p := map[string]interface{}{"key": "val"}
 	err := NewError("code", "A").(ErrorObject)
@@ -0,0 +1,163 @@
+// Copyright 2016 Qiang Xue, 2022 Jellydator. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrExactlyOneOf is the error that returns when not exactly one field of a group is set.
+	ErrExactlyOneOf = NewError("validation_exactly_one_of", "exactly one of {{.fields}} must be set")
+	// ErrAtMostOneOf is the error that returns when more than one field of a group is set.
+	ErrAtMostOneOf = NewError("validation_at_most_one_of", "at most one of {{.fields}} can be set")
+	// ErrAtLeastOneOf is the error that returns when no field of a group is set.
+	ErrAtLeastOneOf = NewError("validation_at_least_one_of", "at least one of {{.fields}} must be set")
+	// ErrAllOrNone is the error that returns when only some fields of a group are set.
+	ErrAllOrNone = NewError("validation_all_or_none", "either all or none of {{.fields}} must be set")
+	// ErrFieldGroupConflict is the error that returns for a field that is set together with other fields of its group.
+	ErrFieldGroupConflict = NewError("validation_field_group_conflict", "cannot be set together with {{.fields}}")
+	// ErrFieldGroupMissing is the error that returns for a field that is not set while other fields of its group are.
+	ErrFieldGroupMissing = NewError("validation_field_group_missing", "must be set together with {{.fields}}")
+)
+
+// FieldGroupRule is a validation rule that checks how many fields of a group are set.
+// A field is set if it is neither nil nor empty, i.e. if it satisfies the Required rule.
+//
+// The rule validates a struct as a whole, so it should be passed to StructRule or Group.
+// When it fails, the returned Errors contain an error for each offending field, keyed by the field name,
+// and the group-level error, which ValidateStruct reports under the key of the StructRule or Group. For example,
+//
+//	err := validation.ValidateStruct(&p,
+//	    validation.StructRule(validation.ExactlyOneOf(&p.Card, &p.Bank, &p.Wallet)),
+//	)
+//	fmt.Println(err)
+//	// Bank: cannot be set together with Card; Card: cannot be set together with Bank; _struct: exactly one of Card, Bank, Wallet must be set.
+type FieldGroupRule struct {
+	fieldPtrs []interface{}
+	names     []string
+	min, max  int
+	err       Error
+}
+
+// ExactlyOneOf returns a validation rule that checks if exactly one of the given struct fields is set.
+func ExactlyOneOf(fieldPtrs ...interface{}) FieldGroupRule {
+	return FieldGroupRule{fieldPtrs: fieldPtrs, min: 1, max: 1, err: ErrExactlyOneOf}
+}
+
+// AtMostOneOf returns a validation rule that checks if at most one of the given struct fields is set.
+func AtMostOneOf(fieldPtrs ...interface{}) FieldGroupRule {
+	return FieldGroupRule{fieldPtrs: fieldPtrs, min: 0, max: 1, err: ErrAtMostOneOf}
+}
+
+// AtLeastOneOf returns a validation rule that checks if at least one of the given struct fields is set.
+func AtLeastOneOf(fieldPtrs ...interface{}) FieldGroupRule {
+	return FieldGroupRule{fieldPtrs: fieldPtrs, min: 1, max: len(fieldPtrs), err: ErrAtLeastOneOf}
+}
+
+// AllOrNone returns a validation rule that checks if either all or none of the given struct fields are set.
+func AllOrNone(fieldPtrs ...interface{}) FieldGroupRule {
+	return FieldGroupRule{fieldPtrs: fieldPtrs, min: -1, max: len(fieldPtrs), err: ErrAllOrNone}
+}
+
+// Error sets the error message of the group-level error.
+func (r FieldGroupRule) Error(message string) FieldGroupRule {
+	r.err = r.err.SetMessage(message)
+	return r
+}
+
+// ErrorObject sets the group-level error struct for the rule.
+func (r FieldGroupRule) ErrorObject(err Error) FieldGroupRule {
+	r.err = err
+	return r
+}
+
+// Validate checks if the given value is valid or not. The value itself is ignored: the rule checks
+// the fields it has been given. Outside of ValidateStruct, the errors are keyed by the positions of the fields.
+func (r FieldGroupRule) Validate(interface{}) error {
+	var set, unset []int
+	for i, fieldPtr := range r.fieldPtrs {
+		if _, ok := referencedValue(fieldPtr); ok {
+			set = append(set, i)
+		} else {
+			unset = append(unset, i)
+		}
+	}
+
+	count := len(set)
+	if r.min < 0 {
+		// all or none
+		if count == 0 || count == r.max {
+			return nil
+		}
+	} else if count >= r.min && count <= r.max {
+		return nil
+	}
+
+	errs := Errors{"": r.err.SetParams(map[string]interface{}{"fields": r.join(nil)})}
+	switch {
+	case r.min < 0:
+		for _, i := range unset {
+			errs[r.name(i)] = ErrFieldGroupMissing.SetParams(map[string]interface{}{"fields": r.join(set)})
+		}
+	case count > r.max:
+		for _, i := range set {
+			others := make([]int, 0, count-1)
+			for _, j := range set {
+				if j != i {
+					others = append(others, j)
+				}
+			}
+			errs[r.name(i)] = ErrFieldGroupConflict.SetParams(map[string]interface{}{"fields": r.join(others)})
+		}
+	}
+	return errs
+}
+
+// bindStruct returns a copy of the rule that knows the names of the fields of the given struct.
+func (r FieldGroupRule) bindStruct(structValue reflect.Value) (Rule, bool) {
+	names, ok := fieldNames(structValue, r.fieldPtrs)
+	if !ok {
+		return nil, false
+	}
+	r.names = names
+	return r, true
+}
+
+// fieldRefs returns the pointers to the fields of the group.
+func (r FieldGroupRule) fieldRefs() []interface{} {
+	return r.fieldPtrs
+}
+
+// withFieldRefs returns a copy of the rule that refers to the given fields instead.
+func (r FieldGroupRule) withFieldRefs(fieldPtrs []interface{}) Rule {
+	r.fieldPtrs = fieldPtrs
+	return r
+}
+
+// name returns the error name of the i-th field.
+func (r FieldGroupRule) name(i int) string {
+	if i < len(r.names) {
+		return r.names[i]
+	}
+	return strconv.Itoa(i)
+}
+
+// join returns the comma separated names of the given fields, or of all fields if indexes is nil.
+func (r FieldGroupRule) join(indexes []int) string {
+	if indexes == nil {
+		indexes = make([]int, len(r.fieldPtrs))
+		for i := range indexes {
+			indexes[i] = i
+		}
+	}
+	names := make([]string, len(indexes))
+	for i, index := range indexes {
+		names[i] = r.name(index)
+	}
+	return strings.Join(names, ", ")
+}
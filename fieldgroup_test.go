@@ -0,0 +1,98 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type cardPayment struct {
+	Number string
+}
+
+type payment struct {
+	Card   *cardPayment `json:"card"`
+	Bank   string       `json:"bank"`
+	Wallet *string      `json:"wallet"`
+}
+
+func TestFieldGroupRule(t *testing.T) {
+	empty := ""
+	wallet := "w1"
+	tests := []struct {
+		tag   string
+		model payment
+		rule  func(p *payment) FieldGroupRule
+		err   string
+	}{
+		// ExactlyOneOf
+		{"t1.1", payment{Bank: "b"}, func(p *payment) FieldGroupRule {
+			return ExactlyOneOf(&p.Card, &p.Bank, &p.Wallet)
+		}, ""},
+		{"t1.2", payment{}, func(p *payment) FieldGroupRule {
+			return ExactlyOneOf(&p.Card, &p.Bank, &p.Wallet)
+		}, "_struct: exactly one of card, bank, wallet must be set."},
+		{"t1.3", payment{Card: &cardPayment{"1"}, Bank: "b", Wallet: &wallet}, func(p *payment) FieldGroupRule {
+			return ExactlyOneOf(&p.Card, &p.Bank, &p.Wallet)
+		}, "_struct: exactly one of card, bank, wallet must be set; bank: cannot be set together with card, wallet; card: cannot be set together with bank, wallet; wallet: cannot be set together with card, bank."},
+		// nil and empty values are not set
+		{"t1.4", payment{Card: &cardPayment{}, Wallet: &empty}, func(p *payment) FieldGroupRule {
+			return ExactlyOneOf(&p.Card, &p.Bank, &p.Wallet)
+		}, "_struct: exactly one of card, bank, wallet must be set."},
+		// AtMostOneOf
+		{"t2.1", payment{}, func(p *payment) FieldGroupRule {
+			return AtMostOneOf(&p.Card, &p.Bank)
+		}, ""},
+		{"t2.2", payment{Card: &cardPayment{"1"}, Bank: "b"}, func(p *payment) FieldGroupRule {
+			return AtMostOneOf(&p.Card, &p.Bank)
+		}, "_struct: at most one of card, bank can be set; bank: cannot be set together with card; card: cannot be set together with bank."},
+		// AtLeastOneOf
+		{"t3.1", payment{Card: &cardPayment{"1"}, Bank: "b"}, func(p *payment) FieldGroupRule {
+			return AtLeastOneOf(&p.Card, &p.Bank)
+		}, ""},
+		{"t3.2", payment{}, func(p *payment) FieldGroupRule {
+			return AtLeastOneOf(&p.Card, &p.Bank)
+		}, "_struct: at least one of card, bank must be set."},
+		// AllOrNone
+		{"t4.1", payment{}, func(p *payment) FieldGroupRule {
+			return AllOrNone(&p.Card, &p.Bank, &p.Wallet)
+		}, ""},
+		{"t4.2", payment{Card: &cardPayment{"1"}, Bank: "b", Wallet: &wallet}, func(p *payment) FieldGroupRule {
+			return AllOrNone(&p.Card, &p.Bank, &p.Wallet)
+		}, ""},
+		{"t4.3", payment{Bank: "b"}, func(p *payment) FieldGroupRule {
+			return AllOrNone(&p.Card, &p.Bank, &p.Wallet)
+		}, "_struct: either all or none of card, bank, wallet must be set; card: must be set together with bank; wallet: must be set together with bank."},
+		// custom error
+		{"t5.1", payment{}, func(p *payment) FieldGroupRule {
+			return AtLeastOneOf(&p.Card, &p.Bank).Error("a payment method is required")
+		}, "_struct: a payment method is required."},
+		{"t5.2", payment{}, func(p *payment) FieldGroupRule {
+			return AtLeastOneOf(&p.Card, &p.Bank).ErrorObject(NewError("payment_required", "payment required"))
+		}, "_struct: payment required."},
+	}
+	for _, test := range tests {
+		p := test.model
+		err := ValidateStruct(&p, StructRule(test.rule(&p)))
+		assertError(t, test.err, err, test.tag)
+	}
+
+	// Group
+	p := payment{}
+	err := ValidateStruct(&p, Group("payment", ExactlyOneOf(&p.Card, &p.Bank)))
+	assertError(t, "payment: exactly one of card, bank must be set.", err, "t6.1")
+
+	// invalid field references
+	err = ValidateStruct(&p, StructRule(ExactlyOneOf(&p.Card, &payment{})))
+	assertError(t, "field #0 refers to a field that cannot be found in the struct", err, "t6.2")
+
+	// outside of a struct, fields are keyed by their positions
+	p = payment{Card: &cardPayment{"1"}, Bank: "b"}
+	err = Validate(nil, AtMostOneOf(&p.Card, &p.Bank))
+	assertError(t, ": at most one of 0, 1 can be set; 0: cannot be set together with 1; 1: cannot be set together with 0.", err, "t6.3")
+
+	// params
+	es := err.(Errors)
+	assert.Equal(t, "validation_at_most_one_of", es[""].(Error).Code())
+	assert.Equal(t, map[string]interface{}{"fields": "0, 1"}, es[""].(Error).Params())
+}
@@ -0,0 +1,256 @@
+// Copyright 2016 Qiang Xue, 2022 Jellydator. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+// PathSyntax determines how the path of a FieldError is rendered.
+type PathSyntax int
+
+// Available path syntaxes.
+const (
+	// PathDotted renders paths such as address.lines[0].
+	PathDotted PathSyntax = iota
+	// PathJSONPointer renders RFC 6901 JSON Pointers such as /address/lines/0.
+	PathJSONPointer
+	// PathJSONPath renders JSONPath expressions such as $.address.lines[0].
+	PathJSONPath
+)
+
+var (
+	jsonPointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+	jsonPathEscaper    = strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+)
+
+// FieldError describes a single validation error found in a tree of validation errors.
+type FieldError struct {
+	// Path is the location of the error, rendered using the requested PathSyntax.
+	Path string `json:"path"`
+	// Code is the error code. It is empty if the error does not implement Error.
+	Code string `json:"code,omitempty"`
+	// Message is the error message.
+	Message string `json:"message"`
+	// Params are the error's template parameters, if any.
+	Params map[string]interface{} `json:"params,omitempty"`
+	// Severity is the error's severity, as returned by ErrorSeverity.
+	Severity Severity `json:"severity,omitempty"`
+}
+
+// Flatten walks the nested Errors and returns a flat list of the errors they contain, ordered by path.
+// Please refer to the Flatten function for more details.
+func (es Errors) Flatten(syntax PathSyntax) []FieldError {
+	return Flatten(es, syntax)
+}
+
+// Flatten walks the given error and returns a flat list of the validation errors it contains, ordered by path.
+// Errors are walked recursively, and their keys become the segments of the path. Keys consisting of digits only
+// (such as the slice indexes used by Each) are rendered as array indexes. Errors wrapped by other errors, either
+// directly or as part of a multi-error, are found using errors.As and the Unwrap methods.
+// Every other error results in a single FieldError whose code and params are taken from the Error it wraps, if any.
+// A nil error results in an empty list.
+func Flatten(err error, syntax PathSyntax) []FieldError {
+	var res []FieldError
+	flatten(err, nil, syntax, &res)
+	return res
+}
+
+func flatten(err error, path []string, syntax PathSyntax, res *[]FieldError) {
+	if err == nil {
+		return
+	}
+
+	if es, ok := err.(Errors); ok {
+		flattenErrors(es, path, syntax, res)
+		return
+	}
+	if u, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range u.Unwrap() {
+			flatten(e, path, syntax, res)
+		}
+		return
+	}
+	var es Errors
+	if errors.As(err, &es) {
+		flattenErrors(es, path, syntax, res)
+		return
+	}
+
+	fe := FieldError{
+		Path:     formatPath(path, syntax),
+		Message:  err.Error(),
+		Severity: ErrorSeverity(err),
+	}
+	var e Error
+	if errors.As(err, &e) {
+		fe.Code = e.Code()
+		fe.Params = e.Params()
+	}
+	*res = append(*res, fe)
+}
+
+func flattenErrors(es Errors, path []string, syntax PathSyntax, res *[]FieldError) {
+	keys := make([]string, 0, len(es))
+	for key := range es {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		flatten(es[key], append(path[:len(path):len(path)], key), syntax, res)
+	}
+}
+
+// HasCode checks if the given error contains a validation error with the given code anywhere in its tree.
+// The tree is walked as Flatten does. An empty code is never found.
+func HasCode(err error, code string) bool {
+	if code == "" {
+		return false
+	}
+	for _, fe := range Flatten(err, PathDotted) {
+		if fe.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// Codes returns the codes of the validation errors contained in the given error, indexed by their paths
+// rendered with PathDotted. The tree is walked as Flatten does. Errors without a code are omitted, and only
+// the first code is kept when several errors are found at the same path, such as those of an ErrorList.
+func Codes(err error) map[string]string {
+	codes := map[string]string{}
+	for _, fe := range Flatten(err, PathDotted) {
+		if _, ok := codes[fe.Path]; !ok && fe.Code != "" {
+			codes[fe.Path] = fe.Code
+		}
+	}
+	return codes
+}
+
+// FindByPath returns the error found at the given path within the given error, or nil if there is none.
+// The result can be a single error or a subtree of Errors. For example,
+//
+//	if errors.Is(validation.FindByPath(err, "address.zip"), validation.ErrLengthTooLong) {
+//	    ...
+//	}
+//
+// The path is rendered as by Flatten: a path starting with "$" uses PathJSONPath, one starting with "/"
+// uses PathJSONPointer, and any other uses PathDotted. An empty path refers to err itself.
+func FindByPath(err error, path string) error {
+	syntax := PathDotted
+	if strings.HasPrefix(path, "$") {
+		syntax = PathJSONPath
+	} else if strings.HasPrefix(path, "/") {
+		syntax = PathJSONPointer
+	}
+	return findByPath(err, nil, path, syntax)
+}
+
+func findByPath(err error, path []string, target string, syntax PathSyntax) error {
+	if err == nil {
+		return nil
+	}
+	if formatPath(path, syntax) == target {
+		return err
+	}
+
+	es, ok := err.(Errors)
+	if !ok {
+		if u, ok := err.(interface{ Unwrap() []error }); ok {
+			for _, e := range u.Unwrap() {
+				if found := findByPath(e, path, target, syntax); found != nil {
+					return found
+				}
+			}
+			return nil
+		}
+		if !errors.As(err, &es) {
+			return nil
+		}
+	}
+
+	keys := make([]string, 0, len(es))
+	for key := range es {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		keyPath := append(path[:len(path):len(path)], key)
+		// the rendered path of a descendant starts with the rendered path of its ancestors
+		if !strings.HasPrefix(target, formatPath(keyPath, syntax)) {
+			continue
+		}
+		if found := findByPath(es[key], keyPath, target, syntax); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// formatPath renders the given path segments using the given syntax.
+func formatPath(path []string, syntax PathSyntax) string {
+	var s strings.Builder
+	switch syntax {
+	case PathJSONPointer:
+		for _, seg := range path {
+			s.WriteByte('/')
+			s.WriteString(jsonPointerEscaper.Replace(seg))
+		}
+	case PathJSONPath:
+		s.WriteByte('$')
+		for _, seg := range path {
+			switch {
+			case isIndexKey(seg):
+				s.WriteString("[" + seg + "]")
+			case isIdentifierKey(seg):
+				s.WriteString("." + seg)
+			default:
+				s.WriteString("['" + jsonPathEscaper.Replace(seg) + "']")
+			}
+		}
+	default:
+		for i, seg := range path {
+			if isIndexKey(seg) {
+				s.WriteString("[" + seg + "]")
+				continue
+			}
+			if i > 0 {
+				s.WriteByte('.')
+			}
+			s.WriteString(seg)
+		}
+	}
+	return s.String()
+}
+
+// isIndexKey checks if an error key looks like a slice or array index.
+func isIndexKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for _, c := range key {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// isIdentifierKey checks if an error key can be used in the dot notation of JSONPath.
+func isIdentifierKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for i, c := range key {
+		if c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || i > 0 && c >= '0' && c <= '9' {
+			continue
+		}
+		return false
+	}
+	return true
+}
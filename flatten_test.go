@@ -0,0 +1,152 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlatten(t *testing.T) {
+	err := Errors{
+		"name": ErrRequired,
+		"address": Errors{
+			"lines": Errors{
+				"0": ErrLengthOutOfRange.SetParams(map[string]interface{}{"min": 1, "max": 5}),
+				"1": nil,
+			},
+			"zip/code": fmt.Errorf("zip: %w", ErrMatchInvalid),
+		},
+		"tags": fmt.Errorf("wrapped: %w", Errors{"a b": errors.New("custom")}),
+		"x~'y": errors.Join(ErrNil, ErrEmpty),
+	}
+
+	assert.Equal(t, []FieldError{
+		{Path: "address.lines[0]", Code: "validation_length_out_of_range", Message: "the length must be between 1 and 5", Params: map[string]interface{}{"min": 1, "max": 5}},
+		{Path: "address.zip/code", Code: "validation_match_invalid", Message: "zip: must be in a valid format"},
+		{Path: "name", Code: "validation_required", Message: "cannot be blank"},
+		{Path: "tags.a b", Message: "custom"},
+		{Path: "x~'y", Code: "validation_nil", Message: "must be blank"},
+		{Path: "x~'y", Code: "validation_empty", Message: "must be blank"},
+	}, err.Flatten(PathDotted))
+
+	paths := func(syntax PathSyntax) []string {
+		var res []string
+		for _, fe := range err.Flatten(syntax) {
+			res = append(res, fe.Path)
+		}
+		return res
+	}
+	assert.Equal(t, []string{"/address/lines/0", "/address/zip~1code", "/name", "/tags/a b", "/x~0'y", "/x~0'y"}, paths(PathJSONPointer))
+	assert.Equal(t, []string{"$.address.lines[0]", "$.address['zip/code']", "$.name", "$.tags['a b']", `$['x~\'y']`, `$['x~\'y']`}, paths(PathJSONPath))
+}
+
+func TestFlatten_Leaf(t *testing.T) {
+	assert.Nil(t, Flatten(nil, PathDotted))
+	assert.Equal(t, []FieldError{{Path: "", Code: "validation_required", Message: "cannot be blank"}}, Flatten(ErrRequired, PathDotted))
+	assert.Equal(t, []FieldError{{Path: "$", Message: "abc"}}, Flatten(errors.New("abc"), PathJSONPath))
+	assert.Equal(t, []FieldError{{Path: "[0]", Message: "abc"}}, Flatten(Errors{"0": errors.New("abc")}, PathDotted))
+}
+
+func TestFlatten_Validate(t *testing.T) {
+	m := Model5{M4: Model4{A: "xyz"}}
+	err := ValidateStructWithContext(context.Background(), &m,
+		Field(&m.Model4),
+		Field(&m.M4),
+		Field(&m.B, Required),
+	)
+	var paths []string
+	for _, fe := range Flatten(err, PathJSONPointer) {
+		paths = append(paths, fe.Path)
+	}
+	assert.Equal(t, []string{"/A", "/B", "/M4/A"}, paths)
+}
+
+func TestFindByPath(t *testing.T) {
+	lines := Errors{
+		"0": ErrLengthOutOfRange.SetParams(map[string]interface{}{"min": 1, "max": 5}),
+		"1": nil,
+	}
+	address := Errors{
+		"lines":    lines,
+		"zip":      ErrLengthTooLong,
+		"zip/code": fmt.Errorf("zip: %w", ErrMatchInvalid),
+	}
+	err := Errors{
+		"name":    ErrRequired,
+		"names":   ErrNil,
+		"address": address,
+		"tags":    fmt.Errorf("wrapped: %w", Errors{"a b": errors.New("custom")}),
+		"x":       errors.Join(ErrNil, Errors{"y": ErrEmpty}),
+	}
+
+	tests := []struct {
+		tag      string
+		path     string
+		expected error
+	}{
+		{"t1.1", "", err},
+		{"t1.2", "name", ErrRequired},
+		{"t1.3", "names", ErrNil},
+		{"t1.4", "address", address},
+		{"t1.5", "address.zip", ErrLengthTooLong},
+		{"t1.6", "address.lines", lines},
+		{"t1.7", "address.lines[0]", lines["0"]},
+		{"t1.8", "address.lines[1]", nil},
+		{"t1.9", "address.lines[2]", nil},
+		{"t1.10", "address.zip/code", address["zip/code"]},
+		{"t1.11", "tags.a b", errors.New("custom")},
+		{"t1.12", "x.y", ErrEmpty},
+		{"t1.13", "name.first", nil},
+		{"t1.14", "unknown", nil},
+		{"t2.1", "/address/zip", ErrLengthTooLong},
+		{"t2.2", "/address/lines/0", lines["0"]},
+		{"t2.3", "/address/zip~1code", address["zip/code"]},
+		{"t3.1", "$", err},
+		{"t3.2", "$.address.lines[0]", lines["0"]},
+		{"t3.3", "$.tags['a b']", errors.New("custom")},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, FindByPath(err, test.path), test.tag)
+	}
+
+	assert.True(t, errors.Is(FindByPath(err, "address.zip"), ErrLengthTooLong))
+	assert.Nil(t, FindByPath(nil, ""))
+	assert.Equal(t, ErrRequired, FindByPath(ErrRequired, ""))
+}
+
+func TestHasCode(t *testing.T) {
+	err := Errors{
+		"name":    ErrRequired,
+		"address": Errors{"zip": fmt.Errorf("zip: %w", ErrMatchInvalid)},
+		"tags":    ErrorList{ErrNil, errors.New("custom")},
+	}
+
+	assert.True(t, HasCode(err, "validation_required"))
+	assert.True(t, HasCode(err, "validation_match_invalid"))
+	assert.True(t, HasCode(err, "validation_nil"))
+	assert.False(t, HasCode(err, "validation_empty"))
+	assert.False(t, HasCode(err, ""))
+	assert.False(t, HasCode(nil, "validation_required"))
+	assert.True(t, HasCode(ErrRequired, "validation_required"))
+}
+
+func TestCodes(t *testing.T) {
+	err := Errors{
+		"name":    ErrRequired,
+		"address": Errors{"lines": Errors{"0": ErrLengthTooLong}},
+		"tags":    ErrorList{ErrNil, ErrEmpty},
+		"custom":  errors.New("custom"),
+	}
+
+	assert.Equal(t, map[string]string{
+		"name":             "validation_required",
+		"address.lines[0]": "validation_length_too_long",
+		"tags":             "validation_nil",
+	}, Codes(err))
+	assert.Equal(t, map[string]string{}, Codes(nil))
+	assert.Equal(t, map[string]string{"": "validation_required"}, Codes(ErrRequired))
+}
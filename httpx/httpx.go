@@ -0,0 +1,255 @@
+// Copyright 2016 Qiang Xue, 2022 Jellydator. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package httpx provides net/http helpers that decode JSON request bodies, validate them,
+// and write problem details responses when either step fails.
+package httpx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/jellydator/validation"
+	"github.com/jellydator/validation/problem"
+)
+
+// DefaultMaxBodySize is the maximum size of a request body used when Decoder.MaxBodySize is zero.
+const DefaultMaxBodySize = 1 << 20
+
+type (
+	// Decoder decodes JSON request bodies and validates the decoded values.
+	// The zero value is ready to use.
+	Decoder struct {
+		// MaxBodySize is the maximum size of a request body in bytes. DefaultMaxBodySize is used if zero,
+		// and no limit is applied if negative.
+		MaxBodySize int64
+		// AllowUnknownFields allows request bodies to contain object keys that do not match any field
+		// of the destination. Such keys are rejected by default.
+		AllowUnknownFields bool
+		// Converter converts errors into the problem details written by WriteError and Handle.
+		// problem.DefaultConverter is used if nil.
+		Converter *problem.Converter
+	}
+
+	// DecodeError is the error returned when a request body cannot be decoded.
+	DecodeError struct {
+		// Status is the HTTP status code that should be responded with.
+		Status int
+		// Err is the underlying error.
+		Err error
+	}
+
+	// warningsKey is the context key of the validation warnings of a request body.
+	warningsKey struct{}
+
+	// valueKey is the context key of the request body decoded into a T by a middleware.
+	valueKey[T any] struct{}
+)
+
+// DefaultDecoder is the Decoder used by DecodeAndValidate, WriteError and Handle.
+var DefaultDecoder = &Decoder{}
+
+// Error returns the error message.
+func (e *DecodeError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeAndValidate decodes the JSON body of r into dst and validates it using DefaultDecoder.
+// Please refer to Decoder.DecodeAndValidate for more details.
+func DecodeAndValidate(r *http.Request, dst interface{}) error {
+	return DefaultDecoder.DecodeAndValidate(r, dst)
+}
+
+// WriteError writes err as a problem details response using DefaultDecoder.
+// Please refer to Decoder.WriteError for more details.
+func WriteError(w http.ResponseWriter, err error) error {
+	return DefaultDecoder.WriteError(w, err)
+}
+
+// Handle returns a handler that decodes and validates the request body into a new T using DefaultDecoder,
+// and calls fn with it. If decoding or validation fails, the error is written as a problem details response
+// and fn is not called. Warnings, as reported by validation.Warn, do not make validation fail: fn is called
+// and can get them by calling Warnings with its request. As the warnings are collected with validation.WithWarnings,
+// T should implement validation.ValidatableWithContext, passing its context along, for them to be found.
+func Handle[T any](fn func(w http.ResponseWriter, r *http.Request, v *T)) http.Handler {
+	return HandleWith(DefaultDecoder, fn)
+}
+
+// HandleWith is like Handle, but uses the given Decoder.
+func HandleWith[T any](d *Decoder, fn func(w http.ResponseWriter, r *http.Request, v *T)) http.Handler {
+	return MiddlewareWith[T](d)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fn(w, r, Value[T](r))
+	}))
+}
+
+// Middleware returns a middleware that decodes and validates the request body into a new T using DefaultDecoder.
+// If decoding or validation fails, the error is written as a problem details response and the next handler
+// is not called. Otherwise, the next handler is called with a request whose context holds the decoded value,
+// which can be retrieved by calling Value, and the validation warnings, which can be retrieved by calling Warnings.
+// For example,
+//
+//	mux.Handle("/customers", httpx.Middleware[Customer]()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+//	    c := httpx.Value[Customer](r)
+//	    // c has been decoded and validated
+//	})))
+//
+// As the request body is required, the middleware should only wrap the handlers of requests that have one.
+func Middleware[T any]() func(http.Handler) http.Handler {
+	return MiddlewareWith[T](DefaultDecoder)
+}
+
+// MiddlewareWith is like Middleware, but uses the given Decoder.
+func MiddlewareWith[T any](d *Decoder) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			v := new(T)
+			var warnings error
+			if err := d.DecodeAndValidate(r.WithContext(validation.WithWarnings(r.Context(), &warnings)), v); err != nil {
+				if warnings != nil {
+					// list the warnings along with the errors
+					err = validation.ErrorList{err, warnings}
+				}
+				_ = d.WriteError(w, err)
+				return
+			}
+			ctx := context.WithValue(r.Context(), valueKey[T]{}, v)
+			if warnings != nil {
+				ctx = context.WithValue(ctx, warningsKey{}, warnings)
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// Value returns the request body decoded into a T by a middleware returned by Middleware or MiddlewareWith,
+// or nil if there is none.
+func Value[T any](r *http.Request) *T {
+	v, _ := r.Context().Value(valueKey[T]{}).(*T)
+	return v
+}
+
+// Warnings returns the validation warnings of the body of a request passed to the function given to Handle
+// or HandleWith, or to the handler wrapped by a middleware, or nil if there are none.
+func Warnings(r *http.Request) error {
+	warnings, _ := r.Context().Value(warningsKey{}).(error)
+	return warnings
+}
+
+// DecodeAndValidate decodes the JSON body of r into dst, which must be a pointer, and validates it
+// by calling validation.ValidateWithContext with the request context. As a result, dst is validated
+// through its Validate or ValidateWithContext method, and the errors are translated if the context carries
+// a validation.Translator.
+//
+// A *DecodeError is returned if the body cannot be decoded: its status is 415 if the content type is not JSON,
+// 413 if the body exceeds the size limit, and 400 otherwise (e.g. for malformed JSON, unknown fields,
+// values of the wrong type, or an empty body). Otherwise, the validation errors are returned, if any. Warnings
+// are not returned: they are stored in the sink set by validation.WithWarnings on the request context, if any.
+func (d *Decoder) DecodeAndValidate(r *http.Request, dst interface{}) error {
+	if err := d.decode(r, dst); err != nil {
+		return err
+	}
+	return validation.ValidateWithContext(r.Context(), dst)
+}
+
+func (d *Decoder) decode(r *http.Request, dst interface{}) error {
+	if ct := r.Header.Get("Content-Type"); ct != "" {
+		mediaType, _, err := mime.ParseMediaType(ct)
+		if err != nil || (mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json")) {
+			return &DecodeError{http.StatusUnsupportedMediaType, fmt.Errorf("unsupported content type %q", ct)}
+		}
+	}
+	if r.Body == nil {
+		return &DecodeError{http.StatusBadRequest, errors.New("request body must not be empty")}
+	}
+
+	body := r.Body
+	if limit := d.maxBodySize(); limit > 0 {
+		body = http.MaxBytesReader(nil, body, limit)
+	}
+	dec := json.NewDecoder(body)
+	if !d.AllowUnknownFields {
+		dec.DisallowUnknownFields()
+	}
+
+	if err := dec.Decode(dst); err != nil {
+		return decodeError(err)
+	}
+	if err := dec.Decode(&struct{}{}); err != io.EOF {
+		if err != nil {
+			if de := decodeError(err); de.Status == http.StatusRequestEntityTooLarge {
+				return de
+			}
+		}
+		return &DecodeError{http.StatusBadRequest, errors.New("request body must contain a single JSON value")}
+	}
+	return nil
+}
+
+func (d *Decoder) maxBodySize() int64 {
+	if d.MaxBodySize == 0 {
+		return DefaultMaxBodySize
+	}
+	return d.MaxBodySize
+}
+
+// decodeError converts an error returned by the JSON decoder into a DecodeError.
+func decodeError(err error) *DecodeError {
+	var (
+		maxBytesErr *http.MaxBytesError
+		syntaxErr   *json.SyntaxError
+		typeErr     *json.UnmarshalTypeError
+	)
+	switch {
+	case errors.As(err, &maxBytesErr):
+		return &DecodeError{http.StatusRequestEntityTooLarge, fmt.Errorf("request body must not be larger than %v bytes", maxBytesErr.Limit)}
+	case errors.Is(err, io.EOF):
+		return &DecodeError{http.StatusBadRequest, errors.New("request body must not be empty")}
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return &DecodeError{http.StatusBadRequest, errors.New("request body contains malformed JSON")}
+	case errors.As(err, &syntaxErr):
+		return &DecodeError{http.StatusBadRequest, fmt.Errorf("request body contains malformed JSON at offset %v", syntaxErr.Offset)}
+	case errors.As(err, &typeErr) && typeErr.Field != "":
+		return &DecodeError{http.StatusBadRequest, fmt.Errorf("request body contains an invalid value for %q", typeErr.Field)}
+	case errors.As(err, &typeErr):
+		return &DecodeError{http.StatusBadRequest, fmt.Errorf("request body contains an invalid value at offset %v", typeErr.Offset)}
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		return &DecodeError{http.StatusBadRequest, fmt.Errorf("request body contains unknown field %v", strings.TrimPrefix(err.Error(), "json: unknown field "))}
+	}
+	return &DecodeError{http.StatusBadRequest, err}
+}
+
+// WriteError writes err as a problem details response. A *DecodeError results in a problem with
+// the status and the message of the error, while any other error is converted by the Decoder's Converter,
+// i.e. validation errors result in 422 responses and internal errors in 500 responses.
+// Nothing is written if err is nil or only holds warnings.
+func (d *Decoder) WriteError(w http.ResponseWriter, err error) error {
+	if err == nil {
+		return nil
+	}
+	var de *DecodeError
+	if errors.As(err, &de) {
+		p := &problem.Problem{
+			Title:  http.StatusText(de.Status),
+			Status: de.Status,
+			Detail: de.Error(),
+		}
+		return p.Write(w)
+	}
+	c := d.Converter
+	if c == nil {
+		c = problem.DefaultConverter
+	}
+	return c.Write(w, err)
+}
@@ -0,0 +1,246 @@
+// Copyright 2016 Qiang Xue, 2022 Jellydator. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package httpx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jellydator/validation"
+	"github.com/jellydator/validation/problem"
+	"github.com/stretchr/testify/assert"
+)
+
+type signup struct {
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+}
+
+func (s signup) Validate() error {
+	return validation.ValidateStruct(&s,
+		validation.Field(&s.Name, validation.Required, validation.Length(2, 10)),
+		validation.Field(&s.Age, validation.Min(18)),
+	)
+}
+
+type account struct {
+	Name     string `json:"name"`
+	Password string `json:"password"`
+}
+
+func (a account) ValidateWithContext(ctx context.Context) error {
+	return validation.ValidateStructWithContext(ctx, &a,
+		validation.Field(&a.Name, validation.Required),
+		validation.Field(&a.Password, validation.Warn(validation.Length(8, 0))),
+	)
+}
+
+type ctxKey int
+
+type contextual struct {
+	Name string `json:"name"`
+}
+
+func (c *contextual) ValidateWithContext(ctx context.Context) error {
+	if ctx.Value(ctxKey(0)) == c.Name {
+		return validation.NewInternalError(errors.New("reserved"))
+	}
+	return nil
+}
+
+func newRequest(body string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	return r
+}
+
+func TestDecodeAndValidate(t *testing.T) {
+	tests := []struct {
+		tag     string
+		decoder *Decoder
+		body    string
+		status  int
+		err     string
+	}{
+		{"t1", &Decoder{}, `{"name":"John","age":20}`, 0, ""},
+		{"t2", &Decoder{}, `{"name":"J","age":10}`, 0, "age: must be no less than 18; name: the length must be between 2 and 10."},
+		{"t3", &Decoder{}, ``, http.StatusBadRequest, "request body must not be empty"},
+		{"t4", &Decoder{}, `{"name":`, http.StatusBadRequest, "request body contains malformed JSON"},
+		{"t5", &Decoder{}, `{"name":}`, http.StatusBadRequest, "request body contains malformed JSON at offset 9"},
+		{"t6", &Decoder{}, `{"name":1}`, http.StatusBadRequest, `request body contains an invalid value for "name"`},
+		{"t7", &Decoder{}, `[1]`, http.StatusBadRequest, "request body contains an invalid value at offset 1"},
+		{"t8", &Decoder{}, `{"name":"John","age":20,"x":1}`, http.StatusBadRequest, `request body contains unknown field "x"`},
+		{"t9", &Decoder{AllowUnknownFields: true}, `{"name":"John","age":20,"x":1}`, 0, ""},
+		{"t10", &Decoder{}, `{"name":"John","age":20} {}`, http.StatusBadRequest, "request body must contain a single JSON value"},
+		{"t11", &Decoder{MaxBodySize: 10}, `{"name":"John","age":20}`, http.StatusRequestEntityTooLarge, "request body must not be larger than 10 bytes"},
+		{"t12", &Decoder{MaxBodySize: 24}, `{"name":"John","age":20}    `, http.StatusRequestEntityTooLarge, "request body must not be larger than 24 bytes"},
+		{"t13", &Decoder{MaxBodySize: -1}, `{"name":"John","age":20}`, 0, ""},
+	}
+	for _, test := range tests {
+		var s signup
+		err := test.decoder.DecodeAndValidate(newRequest(test.body), &s)
+		if test.err == "" {
+			assert.Nil(t, err, test.tag)
+			assert.Equal(t, signup{Name: "John", Age: 20}, s, test.tag)
+			continue
+		}
+		if assert.NotNil(t, err, test.tag) {
+			assert.Equal(t, test.err, err.Error(), test.tag)
+		}
+		var de *DecodeError
+		if test.status == 0 {
+			assert.False(t, errors.As(err, &de), test.tag)
+		} else if assert.True(t, errors.As(err, &de), test.tag) {
+			assert.Equal(t, test.status, de.Status, test.tag)
+		}
+	}
+
+	r := newRequest(`{}`)
+	r.Header.Set("Content-Type", "text/plain")
+	err := DecodeAndValidate(r, &signup{})
+	assert.Equal(t, &DecodeError{http.StatusUnsupportedMediaType, errors.New(`unsupported content type "text/plain"`)}, err)
+
+	r = newRequest(`{"name":"John","age":20}`)
+	r.Header.Set("Content-Type", "application/vnd.api+json; charset=utf-8")
+	assert.Nil(t, DecodeAndValidate(r, &signup{}))
+
+	r = newRequest(`{"name":"John","age":20}`)
+	r.Header.Del("Content-Type")
+	assert.Nil(t, DecodeAndValidate(r, &signup{}))
+
+	// ValidatableWithContext receives the request context
+	r = newRequest(`{"name":"admin"}`)
+	r = r.WithContext(context.WithValue(r.Context(), ctxKey(0), "admin"))
+	err = DecodeAndValidate(r, &contextual{})
+	_, ok := err.(validation.InternalError)
+	assert.True(t, ok)
+	r = newRequest(`{"name":"john"}`)
+	assert.Nil(t, DecodeAndValidate(r.WithContext(context.WithValue(r.Context(), ctxKey(0), "admin")), &contextual{}))
+}
+
+func TestHandle(t *testing.T) {
+	var called *signup
+	h := Handle(func(w http.ResponseWriter, r *http.Request, s *signup) {
+		called = s
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, newRequest(`{"name":"John","age":20}`))
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, &signup{Name: "John", Age: 20}, called)
+
+	called = nil
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, newRequest(`{"name":"J","age":20}`))
+	assert.Nil(t, called)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, problem.ContentType, w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"title":"Unprocessable Entity","status":422,"invalid-params":[{"name":"name","reason":"the length must be between 2 and 10","code":"validation_length_out_of_range","params":{"min":2,"max":10}}]}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, newRequest(`{"name":`))
+	assert.Nil(t, called)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"title":"Bad Request","status":400,"detail":"request body contains malformed JSON"}`, w.Body.String())
+
+	h = HandleWith(&Decoder{Converter: &problem.Converter{Member: "errors"}}, func(w http.ResponseWriter, r *http.Request, c *contextual) {})
+	r := newRequest(`{"name":"admin"}`)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), ctxKey(0), "admin")))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.JSONEq(t, `{"title":"Internal Server Error","status":500}`, w.Body.String())
+}
+
+func TestMiddleware(t *testing.T) {
+	var called *signup
+	var warnings error
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called, warnings = Value[signup](r), Warnings(r)
+		assert.Nil(t, Value[account](r))
+		w.WriteHeader(http.StatusCreated)
+	})
+	h := Middleware[signup]()(next)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, newRequest(`{"name":"John","age":20}`))
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, &signup{Name: "John", Age: 20}, called)
+	assert.Nil(t, warnings)
+
+	called = nil
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, newRequest(`{"name":"J","age":20}`))
+	assert.Nil(t, called)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, problem.ContentType, w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"title":"Unprocessable Entity","status":422,"invalid-params":[{"name":"name","reason":"the length must be between 2 and 10","code":"validation_length_out_of_range","params":{"min":2,"max":10}}]}`, w.Body.String())
+
+	h = MiddlewareWith[signup](&Decoder{MaxBodySize: 10})(next)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, newRequest(`{"name":"John","age":20}`))
+	assert.Nil(t, called)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+
+	var a *account
+	h = Middleware[account]()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		a, warnings = Value[account](r), Warnings(r)
+	}))
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, newRequest(`{"name":"John","password":"secret"}`))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, &account{Name: "John", Password: "secret"}, a)
+	assert.EqualError(t, warnings, "password: the length must be no less than 8.")
+
+	assert.Nil(t, Value[signup](newRequest(`{}`)))
+}
+
+func TestHandle_Warnings(t *testing.T) {
+	var warnings error
+	h := Handle(func(w http.ResponseWriter, r *http.Request, a *account) {
+		warnings = Warnings(r)
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, newRequest(`{"name":"John","password":"long secret"}`))
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Nil(t, warnings)
+
+	// warnings do not make the validation fail
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, newRequest(`{"name":"John","password":"secret"}`))
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.EqualError(t, warnings, "password: the length must be no less than 8.")
+	assert.True(t, validation.IsWarning(warnings))
+
+	// warnings are listed apart from the errors
+	warnings = nil
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, newRequest(`{"password":"secret"}`))
+	assert.Nil(t, warnings)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.JSONEq(t, `{"title":"Unprocessable Entity","status":422,`+
+		`"invalid-params":[{"name":"name","reason":"cannot be blank","code":"validation_required"}],`+
+		`"warnings":[{"name":"password","reason":"the length must be no less than 8","code":"validation_length_too_short","params":{"min":8,"max":0}}]}`, w.Body.String())
+
+	assert.Nil(t, Warnings(newRequest(`{}`)))
+}
+
+func TestWriteError(t *testing.T) {
+	w := httptest.NewRecorder()
+	assert.Nil(t, WriteError(w, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Body.String())
+
+	w = httptest.NewRecorder()
+	assert.Nil(t, (&Decoder{Converter: &problem.Converter{Member: "errors"}}).WriteError(w, validation.Errors{"name": validation.ErrRequired}))
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.JSONEq(t, `{"title":"Unprocessable Entity","status":422,"errors":[{"name":"name","reason":"cannot be blank","code":"validation_required"}]}`, w.Body.String())
+}
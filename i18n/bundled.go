@@ -0,0 +1,58 @@
+// Copyright 2016 Qiang Xue, 2022 Jellydator. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package i18n
+
+import (
+	"embed"
+	"path"
+	"sort"
+	"strings"
+)
+
+//go:embed locales/*.json
+var bundledFS embed.FS
+
+// BundledLocales returns the locales of the bundled translations, in alphabetical order.
+// The bundled translations cover the messages of all errors defined by the validation and is packages.
+func BundledLocales() []string {
+	entries, _ := bundledFS.ReadDir("locales")
+	locales := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		locales = append(locales, strings.TrimSuffix(entry.Name(), path.Ext(entry.Name())))
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// LoadBundled loads the bundled translations of the given locales into the catalog.
+// All bundled locales are loaded if none is given. Messages already in the catalog are replaced,
+// so custom messages should be added after calling LoadBundled.
+func (c *Catalog) LoadBundled(locales ...string) error {
+	if len(locales) == 0 {
+		locales = BundledLocales()
+	}
+	for _, locale := range locales {
+		f, err := bundledFS.Open("locales/" + normalizeLocale(locale) + ".json")
+		if err != nil {
+			return err
+		}
+		err = c.LoadJSON(locale, f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewBundledCatalog creates a Catalog that contains the translations of all bundled locales.
+func NewBundledCatalog() *Catalog {
+	c := NewCatalog()
+	if err := c.LoadBundled(); err != nil {
+		// the bundled translations are verified by the tests
+		panic(err)
+	}
+	return c
+}
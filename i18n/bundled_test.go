@@ -0,0 +1,125 @@
+// Copyright 2016 Qiang Xue, 2022 Jellydator. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package i18n
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/jellydator/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sentinelErrors returns the codes and messages of the errors created by package level NewError calls
+// in the non-test source files of the given directory.
+func sentinelErrors(t *testing.T, dir string) map[string]string {
+	pkgs, err := parser.ParseDir(token.NewFileSet(), dir, func(fi fs.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	require.NoError(t, err)
+
+	errs := map[string]string{}
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				gen, ok := decl.(*ast.GenDecl)
+				if !ok || gen.Tok != token.VAR {
+					continue
+				}
+				for _, spec := range gen.Specs {
+					for _, value := range spec.(*ast.ValueSpec).Values {
+						call, ok := value.(*ast.CallExpr)
+						if !ok || len(call.Args) != 2 || !isNewError(call.Fun) {
+							continue
+						}
+						code, err1 := strconv.Unquote(call.Args[0].(*ast.BasicLit).Value)
+						message, err2 := strconv.Unquote(call.Args[1].(*ast.BasicLit).Value)
+						require.NoError(t, err1)
+						require.NoError(t, err2)
+						errs[code] = message
+					}
+				}
+			}
+		}
+	}
+	return errs
+}
+
+func isNewError(fun ast.Expr) bool {
+	switch f := fun.(type) {
+	case *ast.Ident:
+		return f.Name == "NewError"
+	case *ast.SelectorExpr:
+		return f.Sel.Name == "NewError"
+	}
+	return false
+}
+
+var templateParam = regexp.MustCompile(`{{\s*\.(\w+)\s*}}`)
+
+func templateParams(s string) []string {
+	var params []string
+	for _, m := range templateParam.FindAllStringSubmatch(s, -1) {
+		params = append(params, m[1])
+	}
+	sort.Strings(params)
+	return params
+}
+
+func TestBundled(t *testing.T) {
+	errs := sentinelErrors(t, "..")
+	for code, message := range sentinelErrors(t, "../is") {
+		errs[code] = message
+	}
+	require.Contains(t, errs, validation.ErrRequired.Code())
+	require.Contains(t, errs, "validation_is_email")
+
+	locales := BundledLocales()
+	assert.Equal(t, []string{"de", "es", "fr", "ja", "lt", "pt", "zh"}, locales)
+
+	c := NewBundledCatalog()
+	for _, locale := range locales {
+		for code, message := range errs {
+			if !assert.True(t, c.Has(locale, code), "%v: missing translation of %q", locale, code) {
+				continue
+			}
+			translated, _ := c.Translate(locale, validation.NewError(code, message))
+			assert.Equal(t, templateParams(message), templateParams(translated), "%v: params of %q", locale, code)
+		}
+		for _, code := range bundledCodes(c, locale) {
+			assert.Contains(t, errs, code, "%v: unknown error code", locale)
+		}
+	}
+}
+
+func bundledCodes(c *Catalog, locale string) []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var codes []string
+	for code := range c.messages[locale] {
+		codes = append(codes, code)
+	}
+	return codes
+}
+
+func TestCatalog_LoadBundled(t *testing.T) {
+	c := NewCatalog()
+	assert.Nil(t, c.LoadBundled("de", "fr"))
+	assert.ElementsMatch(t, []string{"de", "fr"}, c.Locales())
+
+	err := validation.ErrLengthOutOfRange.SetParams(map[string]interface{}{"min": 2, "max": 5})
+	assert.Equal(t, "die Länge muss zwischen 2 und 5 liegen", validation.Translate(err, c.Translator("de-CH")).Error())
+
+	assert.NotNil(t, c.LoadBundled("xx"))
+}
@@ -0,0 +1,279 @@
+// Copyright 2016 Qiang Xue, 2022 Jellydator. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package i18n provides message catalogs that translate validation errors based on their codes.
+package i18n
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/jellydator/validation"
+	"gopkg.in/yaml.v2"
+)
+
+type (
+	// Catalog holds translated message templates indexed by locale and error code.
+	// A Catalog is safe for concurrent use.
+	//
+	// A message is either a single template, or a set of plural forms selected by the value of one
+	// of the error's params (e.g. "min"). Templates use the same syntax as the messages of the built-in
+	// errors, e.g. "muss mindestens {{.min}} Zeichen lang sein".
+	Catalog struct {
+		mu            sync.RWMutex
+		messages      map[string]map[string]message
+		fallbacks     map[string][]string
+		defaultLocale string
+	}
+
+	// Message is a translated message with plural forms.
+	Message struct {
+		// Param is the name of the error param whose value selects the plural form.
+		Param string
+		// Forms maps plural categories ("zero", "one", "two", "few", "many" and "other") to message templates.
+		// The "other" form is used when the form of the selected category is missing.
+		Forms map[string]string
+	}
+
+	message struct {
+		param  string
+		forms  map[string]string
+		plural PluralFunc
+	}
+
+	// translator translates errors using the messages of a catalog for a locale.
+	translator struct {
+		catalog *Catalog
+		locale  string
+	}
+)
+
+// NewCatalog creates an empty Catalog.
+func NewCatalog() *Catalog {
+	return &Catalog{
+		messages:  map[string]map[string]message{},
+		fallbacks: map[string][]string{},
+	}
+}
+
+// Add adds the message template for the given error code in the given locale.
+func (c *Catalog) Add(locale, code, template string) {
+	c.add(locale, code, message{forms: map[string]string{"other": template}})
+}
+
+// AddPlural adds the plural forms of the message for the given error code in the given locale.
+func (c *Catalog) AddPlural(locale, code string, m Message) {
+	c.add(locale, code, message{param: m.Param, forms: m.Forms})
+}
+
+func (c *Catalog) add(locale, code string, m message) {
+	locale = normalizeLocale(locale)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.messages[locale] == nil {
+		c.messages[locale] = map[string]message{}
+	}
+	c.messages[locale][code] = m
+}
+
+// SetFallback sets the locales that are looked up, in order, when a message cannot be found for the given locale.
+// Regardless of the fallbacks, the base language of a regional locale (e.g. "de" for "de-AT") and
+// the default locale are always looked up last.
+func (c *Catalog) SetFallback(locale string, fallbacks ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	fs := make([]string, len(fallbacks))
+	for i, f := range fallbacks {
+		fs[i] = normalizeLocale(f)
+	}
+	c.fallbacks[normalizeLocale(locale)] = fs
+}
+
+// SetDefaultLocale sets the locale that is looked up when a message cannot be found in any other locale.
+func (c *Catalog) SetDefaultLocale(locale string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.defaultLocale = normalizeLocale(locale)
+}
+
+// Locales returns the locales that have at least one message in the catalog.
+func (c *Catalog) Locales() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	locales := make([]string, 0, len(c.messages))
+	for locale := range c.messages {
+		locales = append(locales, locale)
+	}
+	return locales
+}
+
+// Has checks if the catalog has a message for the given error code in the given locale, ignoring fallbacks.
+func (c *Catalog) Has(locale, code string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	_, ok := c.messages[normalizeLocale(locale)][code]
+	return ok
+}
+
+// LoadJSON loads the messages of the given locale from a JSON object mapping error codes to messages.
+// A message is either a template string or an object with a "param" key and one key per plural category.
+// For example,
+//
+//	{
+//	    "validation_required": "darf nicht leer sein",
+//	    "validation_length_too_short": {"param": "min", "one": "muss mindestens ein Zeichen lang sein", "other": "muss mindestens {{.min}} Zeichen lang sein"}
+//	}
+func (c *Catalog) LoadJSON(locale string, r io.Reader) error {
+	var data map[string]interface{}
+	if err := json.NewDecoder(r).Decode(&data); err != nil {
+		return err
+	}
+	return c.load(locale, data)
+}
+
+// LoadYAML loads the messages of the given locale from a YAML mapping of error codes to messages.
+// The structure is the same as the one used by LoadJSON.
+func (c *Catalog) LoadYAML(locale string, r io.Reader) error {
+	var data map[string]interface{}
+	if err := yaml.NewDecoder(r).Decode(&data); err != nil {
+		return err
+	}
+	return c.load(locale, data)
+}
+
+func (c *Catalog) load(locale string, data map[string]interface{}) error {
+	for code, value := range data {
+		switch v := value.(type) {
+		case string:
+			c.Add(locale, code, v)
+		case map[string]interface{}:
+			m, err := parseMessage(v)
+			if err != nil {
+				return fmt.Errorf("%v: %w", code, err)
+			}
+			c.AddPlural(locale, code, m)
+		case map[interface{}]interface{}:
+			mv := make(map[string]interface{}, len(v))
+			for k, e := range v {
+				mv[fmt.Sprint(k)] = e
+			}
+			m, err := parseMessage(mv)
+			if err != nil {
+				return fmt.Errorf("%v: %w", code, err)
+			}
+			c.AddPlural(locale, code, m)
+		default:
+			return fmt.Errorf("%v: unsupported message type %T", code, value)
+		}
+	}
+	return nil
+}
+
+// parseMessage parses a message with plural forms from a decoded JSON or YAML object.
+func parseMessage(data map[string]interface{}) (Message, error) {
+	m := Message{Forms: map[string]string{}}
+	for key, value := range data {
+		s, ok := value.(string)
+		if !ok {
+			return m, fmt.Errorf("%v must be a string", key)
+		}
+		if key == "param" {
+			m.Param = s
+			continue
+		}
+		if !isPluralCategory(key) {
+			return m, fmt.Errorf("unknown plural category %q", key)
+		}
+		m.Forms[key] = s
+	}
+	if m.Forms["other"] == "" {
+		return m, errors.New(`the "other" plural form is required`)
+	}
+	return m, nil
+}
+
+// Translator returns a validation.Translator that translates errors into the given locale.
+// Use validation.WithTranslator to select it for a validation.
+func (c *Catalog) Translator(locale string) validation.Translator {
+	return translator{catalog: c, locale: normalizeLocale(locale)}
+}
+
+// Translate translates the given error into the given locale.
+// It returns the message template to use, and false if no message can be found for the error's code.
+func (c *Catalog) Translate(locale string, err validation.Error) (string, bool) {
+	return c.Translator(locale).Translate(err)
+}
+
+// Translate implements validation.Translator.
+func (t translator) Translate(err validation.Error) (string, bool) {
+	c := t.catalog
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, locale := range c.lookupChain(t.locale) {
+		m, ok := c.messages[locale][err.Code()]
+		if !ok {
+			continue
+		}
+		category := "other"
+		if n, ok := pluralOperand(err.Params(), m.param); ok {
+			if m.plural != nil {
+				category = m.plural(n)
+			} else {
+				category = PluralRule(locale)(n)
+			}
+		}
+		if form, ok := m.forms[category]; ok {
+			return form, true
+		}
+		return m.forms["other"], true
+	}
+	return "", false
+}
+
+// lookupChain returns the locales to look up, in order, for the given locale.
+func (c *Catalog) lookupChain(locale string) []string {
+	chain := []string{locale}
+	chain = append(chain, c.fallbacks[locale]...)
+	if base, _, ok := strings.Cut(locale, "-"); ok {
+		chain = append(chain, base)
+	}
+	if c.defaultLocale != "" {
+		chain = append(chain, c.defaultLocale)
+	}
+	return chain
+}
+
+// pluralOperand returns the numeric value of the given param.
+func pluralOperand(params map[string]interface{}, name string) (float64, bool) {
+	if name == "" {
+		return 0, false
+	}
+	v := reflect.ValueOf(params[name])
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
+
+// normalizeLocale converts a locale such as "de_AT" into the canonical lower case form "de-at".
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+}
@@ -0,0 +1,132 @@
+// Copyright 2016 Qiang Xue, 2022 Jellydator. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package i18n
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/jellydator/validation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCatalog_Translate(t *testing.T) {
+	c := NewCatalog()
+	c.Add("de", "validation_required", "darf nicht leer sein")
+	c.AddPlural("de", "validation_length_too_short", Message{
+		Param: "min",
+		Forms: map[string]string{
+			"one":   "muss mindestens ein Zeichen lang sein",
+			"other": "muss mindestens {{.min}} Zeichen lang sein",
+		},
+	})
+	c.Add("de-AT", "validation_nil", "muss leer sein (AT)")
+	c.Add("en", "validation_empty", "must be empty")
+	c.Add("fr", "validation_in_invalid", "doit être une valeur valide")
+	c.SetDefaultLocale("en")
+	c.SetFallback("de-at", "fr")
+
+	tests := []struct {
+		tag    string
+		locale string
+		err    validation.Error
+		msg    string
+		ok     bool
+	}{
+		{"t1", "de", validation.ErrRequired, "darf nicht leer sein", true},
+		{"t2", "de", validation.ErrLengthTooLong, "", false},
+		{"t3", "de", validation.ErrLengthTooShort.SetParams(map[string]interface{}{"min": 1}), "muss mindestens ein Zeichen lang sein", true},
+		{"t4", "de", validation.ErrLengthTooShort.SetParams(map[string]interface{}{"min": 5}), "muss mindestens {{.min}} Zeichen lang sein", true},
+		{"t5", "de", validation.ErrLengthTooShort, "muss mindestens {{.min}} Zeichen lang sein", true},
+		{"t6", "de_AT", validation.ErrRequired, "darf nicht leer sein", true},
+		{"t7", "de-AT", validation.ErrNil, "muss leer sein (AT)", true},
+		{"t8", "de-AT", validation.ErrInInvalid, "doit être une valeur valide", true},
+		{"t9", "de", validation.ErrEmpty, "must be empty", true},
+		{"t10", "de", validation.ErrInInvalid, "", false},
+		{"t11", "ja", validation.ErrMatchInvalid, "", false},
+	}
+	for _, test := range tests {
+		msg, ok := c.Translate(test.locale, test.err)
+		assert.Equal(t, test.ok, ok, test.tag)
+		assert.Equal(t, test.msg, msg, test.tag)
+	}
+
+	locales := c.Locales()
+	sort.Strings(locales)
+	assert.Equal(t, []string{"de", "de-at", "en", "fr"}, locales)
+	assert.True(t, c.Has("de", "validation_required"))
+	assert.False(t, c.Has("de-AT", "validation_required"))
+}
+
+func TestCatalog_LoadJSON(t *testing.T) {
+	c := NewCatalog()
+	err := c.LoadJSON("lt", strings.NewReader(`{
+		"validation_required": "negali būti tuščias",
+		"validation_length_too_long": {"param": "max", "one": "ne daugiau kaip {{.max}} simbolis", "few": "ne daugiau kaip {{.max}} simboliai", "other": "ne daugiau kaip {{.max}} simbolių"}
+	}`))
+	assert.NoError(t, err)
+
+	tr := c.Translator("lt")
+	msg, _ := tr.Translate(validation.ErrRequired)
+	assert.Equal(t, "negali būti tuščias", msg)
+	msg, _ = tr.Translate(validation.ErrLengthTooLong.SetParams(map[string]interface{}{"max": 21}))
+	assert.Equal(t, "ne daugiau kaip {{.max}} simbolis", msg)
+	msg, _ = tr.Translate(validation.ErrLengthTooLong.SetParams(map[string]interface{}{"max": 3}))
+	assert.Equal(t, "ne daugiau kaip {{.max}} simboliai", msg)
+	msg, _ = tr.Translate(validation.ErrLengthTooLong.SetParams(map[string]interface{}{"max": 10}))
+	assert.Equal(t, "ne daugiau kaip {{.max}} simbolių", msg)
+
+	assert.Error(t, c.LoadJSON("lt", strings.NewReader(`{`)))
+	assert.EqualError(t, c.LoadJSON("lt", strings.NewReader(`{"a": 1}`)), "a: unsupported message type float64")
+	assert.EqualError(t, c.LoadJSON("lt", strings.NewReader(`{"a": {"one": "x"}}`)), `a: the "other" plural form is required`)
+	assert.EqualError(t, c.LoadJSON("lt", strings.NewReader(`{"a": {"some": "x"}}`)), `a: unknown plural category "some"`)
+	assert.EqualError(t, c.LoadJSON("lt", strings.NewReader(`{"a": {"other": 1}}`)), `a: other must be a string`)
+}
+
+func TestCatalog_LoadYAML(t *testing.T) {
+	c := NewCatalog()
+	err := c.LoadYAML("es", strings.NewReader(`
+validation_required: no puede estar vacío
+validation_length_too_short:
+  param: min
+  one: debe tener al menos un carácter
+  other: debe tener al menos {{.min}} caracteres
+`))
+	assert.NoError(t, err)
+
+	msg, _ := c.Translate("es", validation.ErrRequired)
+	assert.Equal(t, "no puede estar vacío", msg)
+	msg, _ = c.Translate("es", validation.ErrLengthTooShort.SetParams(map[string]interface{}{"min": uint(1)}))
+	assert.Equal(t, "debe tener al menos un carácter", msg)
+	msg, _ = c.Translate("es", validation.ErrLengthTooShort.SetParams(map[string]interface{}{"min": 2.0}))
+	assert.Equal(t, "debe tener al menos {{.min}} caracteres", msg)
+
+	assert.Error(t, c.LoadYAML("es", strings.NewReader("a: [")))
+	assert.EqualError(t, c.LoadYAML("es", strings.NewReader("a:\n  one: x\n")), `a: the "other" plural form is required`)
+}
+
+func TestCatalog_WithContext(t *testing.T) {
+	c := NewCatalog()
+	c.Add("de", "validation_required", "darf nicht leer sein")
+	c.Add("de", "validation_length_out_of_range", "muss zwischen {{.min}} und {{.max}} Zeichen lang sein")
+	c.Add("fr", "validation_required", "ne peut pas être vide")
+
+	s := struct {
+		Name string
+		Code string
+	}{Code: "abcdef"}
+	validate := func(locale string) error {
+		ctx := validation.WithTranslator(context.Background(), c.Translator(locale))
+		return validation.ValidateStructWithContext(ctx, &s,
+			validation.Field(&s.Name, validation.Required),
+			validation.Field(&s.Code, validation.Length(1, 3)),
+		)
+	}
+	assert.EqualError(t, validate("de"), "Code: muss zwischen 1 und 3 Zeichen lang sein; Name: darf nicht leer sein.")
+	assert.EqualError(t, validate("fr"), "Code: the length must be between 1 and 3; Name: ne peut pas être vide.")
+	assert.EqualError(t, validation.ValidateStruct(&s, validation.Field(&s.Name, validation.Required)), "Name: cannot be blank.")
+}
@@ -0,0 +1,146 @@
+// Copyright 2016 Qiang Xue, 2022 Jellydator. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package i18n
+
+import (
+	"math"
+	"strings"
+	"sync"
+)
+
+// PluralFunc returns the plural category ("zero", "one", "two", "few", "many" or "other") of a number.
+type PluralFunc func(n float64) string
+
+var (
+	pluralRulesMu sync.RWMutex
+	pluralRules   = map[string]PluralFunc{
+		"ja": pluralOther,
+		"ko": pluralOther,
+		"zh": pluralOther,
+		"vi": pluralOther,
+		"th": pluralOther,
+		"id": pluralOther,
+		"fr": pluralFrench,
+		"pt": pluralFrench,
+		"lt": pluralLithuanian,
+		"ru": pluralRussian,
+		"uk": pluralRussian,
+		"pl": pluralPolish,
+		"cs": pluralCzech,
+		"sk": pluralCzech,
+	}
+)
+
+// RegisterPluralRule registers the plural rule of the given language, replacing the existing one.
+func RegisterPluralRule(lang string, fn PluralFunc) {
+	pluralRulesMu.Lock()
+	defer pluralRulesMu.Unlock()
+
+	pluralRules[normalizeLocale(lang)] = fn
+}
+
+// PluralRule returns the plural rule of the given locale. The rule of the base language is used
+// for regional locales without a specific rule. Languages without a registered rule use the English rule,
+// which distinguishes "one" from "other".
+func PluralRule(locale string) PluralFunc {
+	pluralRulesMu.RLock()
+	defer pluralRulesMu.RUnlock()
+
+	locale = normalizeLocale(locale)
+	if fn, ok := pluralRules[locale]; ok {
+		return fn
+	}
+	if base, _, ok := strings.Cut(locale, "-"); ok {
+		if fn, ok := pluralRules[base]; ok {
+			return fn
+		}
+	}
+	return pluralEnglish
+}
+
+// isPluralCategory checks if the given name is a CLDR plural category.
+func isPluralCategory(name string) bool {
+	switch name {
+	case "zero", "one", "two", "few", "many", "other":
+		return true
+	}
+	return false
+}
+
+// integerOperand returns the integer value of n and whether n has no fraction digits.
+func integerOperand(n float64) (int64, bool) {
+	n = math.Abs(n)
+	return int64(n), n == math.Trunc(n)
+}
+
+func pluralOther(float64) string {
+	return "other"
+}
+
+func pluralEnglish(n float64) string {
+	if i, ok := integerOperand(n); ok && i == 1 {
+		return "one"
+	}
+	return "other"
+}
+
+func pluralFrench(n float64) string {
+	if i, _ := integerOperand(n); i == 0 || i == 1 {
+		return "one"
+	}
+	return "other"
+}
+
+func pluralLithuanian(n float64) string {
+	i, ok := integerOperand(n)
+	switch {
+	case !ok:
+		return "many"
+	case i%10 == 1 && (i%100 < 11 || i%100 > 19):
+		return "one"
+	case i%10 >= 2 && (i%100 < 11 || i%100 > 19):
+		return "few"
+	}
+	return "other"
+}
+
+func pluralRussian(n float64) string {
+	i, ok := integerOperand(n)
+	switch {
+	case !ok:
+		return "other"
+	case i%10 == 1 && i%100 != 11:
+		return "one"
+	case i%10 >= 2 && i%10 <= 4 && (i%100 < 12 || i%100 > 14):
+		return "few"
+	}
+	return "many"
+}
+
+func pluralPolish(n float64) string {
+	i, ok := integerOperand(n)
+	switch {
+	case !ok:
+		return "other"
+	case i == 1:
+		return "one"
+	case i%10 >= 2 && i%10 <= 4 && (i%100 < 12 || i%100 > 14):
+		return "few"
+	}
+	return "many"
+}
+
+func pluralCzech(n float64) string {
+	i, ok := integerOperand(n)
+	switch {
+	case !ok:
+		return "many"
+	case i == 1:
+		return "one"
+	case i >= 2 && i <= 4:
+		return "few"
+	}
+	return "other"
+}
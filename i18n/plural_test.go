@@ -0,0 +1,65 @@
+// Copyright 2016 Qiang Xue, 2022 Jellydator. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package i18n
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPluralRule(t *testing.T) {
+	tests := []struct {
+		locale string
+		n      float64
+		want   string
+	}{
+		{"en", 1, "one"},
+		{"en", 0, "other"},
+		{"en", 1.5, "other"},
+		{"de-AT", 1, "one"},
+		{"unknown", 2, "other"},
+		{"fr", 0, "one"},
+		{"fr", 1.5, "one"},
+		{"fr", 2, "other"},
+		{"pt_BR", 1, "one"},
+		{"ja", 1, "other"},
+		{"zh-Hans", 1, "other"},
+		{"lt", 1, "one"},
+		{"lt", 21, "one"},
+		{"lt", 11, "other"},
+		{"lt", 2, "few"},
+		{"lt", 19, "other"},
+		{"lt", 10, "other"},
+		{"lt", 0.5, "many"},
+		{"ru", 1, "one"},
+		{"ru", 3, "few"},
+		{"ru", 12, "many"},
+		{"ru", 5, "many"},
+		{"ru", 1.5, "other"},
+		{"pl", 1, "one"},
+		{"pl", 22, "few"},
+		{"pl", 21, "many"},
+		{"pl", 1.5, "other"},
+		{"cs", 1, "one"},
+		{"cs", 4, "few"},
+		{"cs", 5, "other"},
+		{"cs", 1.5, "many"},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.want, PluralRule(test.locale)(test.n), "%v %v", test.locale, test.n)
+	}
+}
+
+func TestRegisterPluralRule(t *testing.T) {
+	RegisterPluralRule("x-test", func(n float64) string {
+		if n == 2 {
+			return "two"
+		}
+		return "other"
+	})
+	assert.Equal(t, "two", PluralRule("x-test")(2))
+	assert.Equal(t, "other", PluralRule("x-test")(1))
+}
@@ -0,0 +1,417 @@
+// Copyright 2016 Qiang Xue, 2022 Jellydator. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package i18n
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// poEntry is a single entry of a gettext .po file.
+type poEntry struct {
+	param  string
+	msgid  string
+	plural bool
+	msgstr map[int]string
+	fuzzy  bool
+}
+
+// LoadPO loads the messages of the given locale from a gettext .po file.
+// The msgid of an entry is the error code and its msgstr is the message template.
+// Entries with plural forms (msgid_plural and msgstr[N]) select their form using the Plural-Forms
+// expression of the file header, evaluated with the value of the error param named by an extracted
+// comment of the form "#. param: min". Fuzzy and obsolete entries are ignored. For example,
+//
+//	msgid ""
+//	msgstr "Plural-Forms: nplurals=2; plural=(n != 1);\n"
+//
+//	msgid "validation_required"
+//	msgstr "darf nicht leer sein"
+//
+//	#. param: min
+//	msgid "validation_length_too_short"
+//	msgid_plural "validation_length_too_short"
+//	msgstr[0] "muss mindestens ein Zeichen lang sein"
+//	msgstr[1] "muss mindestens {{.min}} Zeichen lang sein"
+func (c *Catalog) LoadPO(locale string, r io.Reader) error {
+	entries, err := parsePO(r)
+	if err != nil {
+		return err
+	}
+
+	var plural PluralFunc
+	for _, e := range entries {
+		if e.msgid == "" {
+			if plural, err = parsePOHeader(e.msgstr[0]); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, e := range entries {
+		if e.msgid == "" || e.fuzzy {
+			continue
+		}
+		if !e.plural {
+			if e.msgstr[0] != "" {
+				c.Add(locale, e.msgid, e.msgstr[0])
+			}
+			continue
+		}
+		if plural == nil {
+			return fmt.Errorf("%v: plural forms require a Plural-Forms header", e.msgid)
+		}
+		m := message{param: e.param, forms: map[string]string{}, plural: plural}
+		for i, s := range e.msgstr {
+			m.forms[strconv.Itoa(i)] = s
+		}
+		// the first form is used if the param is missing or not a number
+		m.forms["other"] = e.msgstr[0]
+		c.add(locale, e.msgid, m)
+	}
+	return nil
+}
+
+// parsePO parses the entries of a .po file. Obsolete entries are skipped.
+func parsePO(r io.Reader) ([]*poEntry, error) {
+	var (
+		entries []*poEntry
+		entry   *poEntry
+		// appendTo appends continuation lines to the string that was last started
+		appendTo func(string)
+		lineNo   int
+	)
+	flush := func() {
+		if entry != nil && len(entry.msgstr) > 0 {
+			entries = append(entries, entry)
+		}
+		entry = nil
+		appendTo = nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "":
+			flush()
+			continue
+		case strings.HasPrefix(line, "#~"):
+			continue
+		case strings.HasPrefix(line, "#"):
+			if entry != nil && len(entry.msgstr) > 0 {
+				// a comment after the translations of an entry starts a new entry
+				flush()
+			}
+			if entry == nil {
+				entry = &poEntry{msgstr: map[int]string{}}
+			}
+			if strings.HasPrefix(line, "#,") {
+				entry.fuzzy = entry.fuzzy || strings.Contains(line, "fuzzy")
+			} else if strings.HasPrefix(line, "#.") {
+				if name, value, ok := strings.Cut(line[2:], ":"); ok && strings.TrimSpace(name) == "param" {
+					entry.param = strings.TrimSpace(value)
+				}
+			}
+			continue
+		}
+
+		keyword, rest := "", line
+		if !strings.HasPrefix(line, `"`) {
+			keyword, rest, _ = strings.Cut(line, " ")
+		}
+		s, err := strconv.Unquote(strings.TrimSpace(rest))
+		if err != nil {
+			return nil, fmt.Errorf("line %v: invalid string %v", lineNo, rest)
+		}
+
+		if keyword == "" {
+			if appendTo == nil {
+				return nil, fmt.Errorf("line %v: unexpected string", lineNo)
+			}
+			appendTo(s)
+			continue
+		}
+
+		if (keyword == "msgctxt" || keyword == "msgid") && entry != nil && len(entry.msgstr) > 0 {
+			flush()
+		}
+		if entry == nil {
+			entry = &poEntry{msgstr: map[int]string{}}
+		}
+		e := entry
+
+		switch {
+		case keyword == "msgctxt":
+			appendTo = func(string) {}
+		case keyword == "msgid":
+			e.msgid = s
+			appendTo = func(s string) { e.msgid += s }
+		case keyword == "msgid_plural":
+			e.plural = true
+			appendTo = func(string) {}
+		case keyword == "msgstr":
+			e.msgstr[0] = s
+			appendTo = func(s string) { e.msgstr[0] += s }
+		case strings.HasPrefix(keyword, "msgstr[") && strings.HasSuffix(keyword, "]"):
+			i, err := strconv.Atoi(keyword[len("msgstr[") : len(keyword)-1])
+			if err != nil || i < 0 {
+				return nil, fmt.Errorf("line %v: invalid keyword %v", lineNo, keyword)
+			}
+			e.msgstr[i] = s
+			appendTo = func(s string) { e.msgstr[i] += s }
+		default:
+			return nil, fmt.Errorf("line %v: unknown keyword %v", lineNo, keyword)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+	return entries, nil
+}
+
+// parsePOHeader returns the plural rule defined by the Plural-Forms field of a .po file header, if any.
+func parsePOHeader(header string) (PluralFunc, error) {
+	for _, line := range strings.Split(header, "\n") {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok || !strings.EqualFold(strings.TrimSpace(name), "Plural-Forms") {
+			continue
+		}
+		for _, field := range strings.Split(value, ";") {
+			name, expr, ok := strings.Cut(field, "=")
+			if !ok || strings.TrimSpace(name) != "plural" {
+				continue
+			}
+			eval, err := parsePluralExpr(expr)
+			if err != nil {
+				return nil, fmt.Errorf("invalid Plural-Forms expression: %w", err)
+			}
+			return func(n float64) string {
+				return strconv.FormatInt(eval(int64(math.Abs(n))), 10)
+			}, nil
+		}
+	}
+	return nil, nil
+}
+
+// parsePluralExpr compiles a gettext plural expression, which uses the C syntax, into a function of n.
+func parsePluralExpr(expr string) (func(n int64) int64, error) {
+	p := &pluralParser{}
+	if err := p.tokenize(expr); err != nil {
+		return nil, err
+	}
+	fn, err := p.ternary()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q", p.tokens[p.pos])
+	}
+	return fn, nil
+}
+
+type pluralParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *pluralParser) tokenize(expr string) error {
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case c >= '0' && c <= '9':
+			j := i
+			for j < len(expr) && expr[j] >= '0' && expr[j] <= '9' {
+				j++
+			}
+			p.tokens = append(p.tokens, expr[i:j])
+			i = j
+		case i+1 < len(expr) && isPluralOperator(expr[i:i+2]):
+			p.tokens = append(p.tokens, expr[i:i+2])
+			i += 2
+		case strings.IndexByte("n?:<>+-*/%!()", c) >= 0:
+			p.tokens = append(p.tokens, string(c))
+			i++
+		default:
+			return fmt.Errorf("unexpected character %q", c)
+		}
+	}
+	return nil
+}
+
+// isPluralOperator checks if s is a binary operator of a plural expression.
+func isPluralOperator(s string) bool {
+	for _, ops := range pluralOperators {
+		if containsString(ops, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *pluralParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *pluralParser) next() string {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+func (p *pluralParser) ternary() (func(int64) int64, error) {
+	cond, err := p.binary(0)
+	if err != nil || p.peek() != "?" {
+		return cond, err
+	}
+	p.next()
+	yes, err := p.ternary()
+	if err != nil {
+		return nil, err
+	}
+	if p.next() != ":" {
+		return nil, errors.New(`expected ":"`)
+	}
+	no, err := p.ternary()
+	if err != nil {
+		return nil, err
+	}
+	return func(n int64) int64 {
+		if cond(n) != 0 {
+			return yes(n)
+		}
+		return no(n)
+	}, nil
+}
+
+// pluralOperators lists the binary operators by increasing precedence.
+var pluralOperators = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!="},
+	{"<", ">", "<=", ">="},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func (p *pluralParser) binary(level int) (func(int64) int64, error) {
+	if level == len(pluralOperators) {
+		return p.unary()
+	}
+	left, err := p.binary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := p.peek()
+		if !containsString(pluralOperators[level], op) {
+			return left, nil
+		}
+		p.next()
+		right, err := p.binary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = binaryPluralOp(op, left, right)
+	}
+}
+
+func (p *pluralParser) unary() (func(int64) int64, error) {
+	switch t := p.next(); {
+	case t == "!":
+		operand, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return func(n int64) int64 { return boolToInt(operand(n) == 0) }, nil
+	case t == "(":
+		inner, err := p.ternary()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, errors.New(`expected ")"`)
+		}
+		return inner, nil
+	case t == "n":
+		return func(n int64) int64 { return n }, nil
+	case t != "" && t[0] >= '0' && t[0] <= '9':
+		v, err := strconv.ParseInt(t, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return func(int64) int64 { return v }, nil
+	case t == "":
+		return nil, errors.New("unexpected end of expression")
+	default:
+		return nil, fmt.Errorf("unexpected %q", t)
+	}
+}
+
+func binaryPluralOp(op string, left, right func(int64) int64) func(int64) int64 {
+	return func(n int64) int64 {
+		l, r := left(n), right(n)
+		switch op {
+		case "||":
+			return boolToInt(l != 0 || r != 0)
+		case "&&":
+			return boolToInt(l != 0 && r != 0)
+		case "==":
+			return boolToInt(l == r)
+		case "!=":
+			return boolToInt(l != r)
+		case "<":
+			return boolToInt(l < r)
+		case ">":
+			return boolToInt(l > r)
+		case "<=":
+			return boolToInt(l <= r)
+		case ">=":
+			return boolToInt(l >= r)
+		case "+":
+			return l + r
+		case "-":
+			return l - r
+		case "*":
+			return l * r
+		}
+		if r == 0 {
+			return 0
+		}
+		if op == "/" {
+			return l / r
+		}
+		return l % r
+	}
+}
+
+func boolToInt(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func containsString(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+// Copyright 2016 Qiang Xue, 2022 Jellydator. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package i18n
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jellydator/validation"
+	"github.com/stretchr/testify/assert"
+)
+
+const testPO = `# Lithuanian translations
+msgid ""
+msgstr ""
+"Language: lt\n"
+"Plural-Forms: nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && (n%100<10 || n%100>=20) ? 1 : 2);\n"
+
+msgid "validation_required"
+msgstr "negali būti "
+"tuščias"
+
+#. param: max
+msgid "validation_length_too_long"
+msgid_plural "validation_length_too_long"
+msgstr[0] "ne daugiau kaip {{.max}} simbolis"
+msgstr[1] "ne daugiau kaip {{.max}} simboliai"
+msgstr[2] "ne daugiau kaip {{.max}} simbolių"
+
+#, fuzzy
+msgid "validation_nil"
+msgstr "turi būti tuščias"
+
+msgid "validation_empty"
+msgstr ""
+
+#~ msgid "validation_in_invalid"
+#~ msgstr "turi būti tinkama reikšmė"
+`
+
+func TestCatalog_LoadPO(t *testing.T) {
+	c := NewCatalog()
+	assert.NoError(t, c.LoadPO("lt", strings.NewReader(testPO)))
+
+	msg, _ := c.Translate("lt", validation.ErrRequired)
+	assert.Equal(t, "negali būti tuščias", msg)
+
+	tests := []struct {
+		max  int
+		want string
+	}{
+		{1, "ne daugiau kaip {{.max}} simbolis"},
+		{21, "ne daugiau kaip {{.max}} simbolis"},
+		{11, "ne daugiau kaip {{.max}} simbolių"},
+		{3, "ne daugiau kaip {{.max}} simboliai"},
+		{10, "ne daugiau kaip {{.max}} simbolių"},
+	}
+	for _, test := range tests {
+		msg, _ := c.Translate("lt", validation.ErrLengthTooLong.SetParams(map[string]interface{}{"max": test.max}))
+		assert.Equal(t, test.want, msg, test.max)
+	}
+
+	msg, _ = c.Translate("lt", validation.ErrLengthTooLong)
+	assert.Equal(t, "ne daugiau kaip {{.max}} simbolis", msg)
+
+	assert.False(t, c.Has("lt", "validation_nil"))
+	assert.False(t, c.Has("lt", "validation_empty"))
+	assert.False(t, c.Has("lt", "validation_in_invalid"))
+}
+
+func TestCatalog_LoadPO_Errors(t *testing.T) {
+	tests := []struct {
+		tag string
+		po  string
+		err string
+	}{
+		{"t1", `msgid "a`, `line 1: invalid string "a`},
+		{"t2", `"a"`, `line 1: unexpected string`},
+		{"t3", `msgfoo "a"`, `line 1: unknown keyword msgfoo`},
+		{"t4", `msgstr[x] "a"`, `line 1: invalid keyword msgstr[x]`},
+		{"t5", "msgid \"a\"\nmsgid_plural \"a\"\nmsgstr[0] \"b\"", `a: plural forms require a Plural-Forms header`},
+		{"t6", "msgid \"\"\nmsgstr \"Plural-Forms: nplurals=2; plural=(n != 1;\\n\"", `invalid Plural-Forms expression: expected ")"`},
+	}
+	for _, test := range tests {
+		err := NewCatalog().LoadPO("de", strings.NewReader(test.po))
+		assert.EqualError(t, err, test.err, test.tag)
+	}
+}
+
+func Test_parsePluralExpr(t *testing.T) {
+	tests := []struct {
+		expr string
+		n    int64
+		want int64
+	}{
+		{"0", 5, 0},
+		{"n != 1", 1, 0},
+		{"n != 1", 2, 1},
+		{"n>1", 1, 0},
+		{"(n==0 ? 0 : n==1 ? 1 : 2)", 0, 0},
+		{"(n==0 ? 0 : n==1 ? 1 : 2)", 1, 1},
+		{"(n==0 ? 0 : n==1 ? 1 : 2)", 7, 2},
+		{"!(n <= 2) + n * 2 - n / 2 % 3", 4, 1 + 8 - 2},
+		{"n >= 2 || n < 0 && 1", 1, 0},
+		{"n % 0 + n / 0", 5, 0},
+	}
+	for _, test := range tests {
+		fn, err := parsePluralExpr(test.expr)
+		if assert.NoError(t, err, test.expr) {
+			assert.Equal(t, test.want, fn(test.n), test.expr)
+		}
+	}
+
+	for _, expr := range []string{"", "n ?", "n ? 1", "n ? 1 : ", "(n", "n n", "x", "99999999999999999999"} {
+		_, err := parsePluralExpr(expr)
+		assert.Error(t, err, expr)
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2016 Qiang Xue, 2022 Jellydator. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package is
+
+import (
+	"fmt"
+	"reflect"
+
+	validation "github.com/jellydator/validation"
+)
+
+// tagRules maps the names accepted by the is=name validation tag token to the corresponding rules.
+var tagRules = map[string]validation.Rule{
+	"email":              Email,
+	"email_format":       EmailFormat,
+	"url":                URL,
+	"request_url":        RequestURL,
+	"request_uri":        RequestURI,
+	"alpha":              Alpha,
+	"digit":              Digit,
+	"alphanumeric":       Alphanumeric,
+	"utf_letter":         UTFLetter,
+	"utf_digit":          UTFDigit,
+	"utf_letter_numeric": UTFLetterNumeric,
+	"utf_numeric":        UTFNumeric,
+	"lower_case":         LowerCase,
+	"upper_case":         UpperCase,
+	"hexadecimal":        Hexadecimal,
+	"hex_color":          HexColor,
+	"rgb_color":          RGBColor,
+	"int":                Int,
+	"float":              Float,
+	"uuid_v3":            UUIDv3,
+	"uuid_v4":            UUIDv4,
+	"uuid_v5":            UUIDv5,
+	"uuid":               UUID,
+	"ulid":               ULID,
+	"credit_card":        CreditCard,
+	"isbn10":             ISBN10,
+	"isbn13":             ISBN13,
+	"isbn":               ISBN,
+	"json":               JSON,
+	"ascii":              ASCII,
+	"printable_ascii":    PrintableASCII,
+	"multibyte":          Multibyte,
+	"full_width":         FullWidth,
+	"half_width":         HalfWidth,
+	"variable_width":     VariableWidth,
+	"base64":             Base64,
+	"data_uri":           DataURI,
+	"e164":               E164,
+	"country_code2":      CountryCode2,
+	"country_code3":      CountryCode3,
+	"currency_code":      CurrencyCode,
+	"dial_string":        DialString,
+	"mac":                MAC,
+	"ip":                 IP,
+	"ipv4":               IPv4,
+	"ipv6":               IPv6,
+	"subdomain":          Subdomain,
+	"domain":             Domain,
+	"dns_name":           DNSName,
+	"host":               Host,
+	"port":               Port,
+	"mongo_id":           MongoID,
+	"latitude":           Latitude,
+	"longitude":          Longitude,
+	"ssn":                SSN,
+	"semver":             Semver,
+}
+
+// init registers the is=name validation tag token. See validation.ValidateTagged for more details.
+func init() {
+	validation.RegisterTagRule("is", func(param string, _ reflect.Type) (validation.Rule, error) {
+		if rule, ok := tagRules[param]; ok {
+			return rule, nil
+		}
+		return nil, fmt.Errorf("unknown rule %q", param)
+	})
+}
@@ -0,0 +1,27 @@
+// Copyright 2016 Qiang Xue, 2022 Jellydator. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package is
+
+import (
+	"testing"
+
+	"github.com/jellydator/validation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTagRules(t *testing.T) {
+	v := struct {
+		Email string `json:"email" validate:"required,is=email_format"`
+		ID    string `json:"id" validate:"is=uuid_v4"`
+	}{Email: "abc", ID: "123"}
+
+	err := validation.ValidateTagged(&v)
+	assert.EqualError(t, err, "email: must be a valid email address; id: must be a valid UUID v4.")
+
+	u := struct {
+		A string `validate:"is=unknown"`
+	}{}
+	assert.EqualError(t, validation.ValidateTagged(&u), `field A: invalid validation rule "is=unknown": unknown rule "unknown"`)
+}
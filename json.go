@@ -0,0 +1,338 @@
+// Copyright 2016 Qiang Xue, 2022 Jellydator. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// JSONFormat determines how validation errors are encoded into JSON.
+type JSONFormat int
+
+// Available JSON formats.
+const (
+	// JSONMessages encodes every error as its message, e.g. {"name":"cannot be blank"}, omitting the warnings.
+	// It is the format used by Errors.MarshalJSON and ErrorList.MarshalJSON.
+	JSONMessages JSONFormat = iota
+	// JSONStructured encodes every error as an object holding its code, message, params and severity,
+	// e.g. {"name":{"code":"validation_required","message":"cannot be blank"}}.
+	JSONStructured
+)
+
+// JSONEncoder encodes validation errors into JSON using a given format.
+// The zero value uses JSONMessages.
+type JSONEncoder struct {
+	// Format is the format of the encoded errors.
+	Format JSONFormat
+}
+
+// jsonError is the JSONStructured encoding of a single validation error.
+type jsonError struct {
+	Code     string                 `json:"code,omitempty"`
+	Message  string                 `json:"message"`
+	Params   map[string]interface{} `json:"params,omitempty"`
+	Severity Severity               `json:"severity,omitempty"`
+}
+
+// MarshalErrorJSON encodes the given error into JSON using the given format.
+// Please refer to JSONEncoder.Marshal for more details.
+func MarshalErrorJSON(err error, format JSONFormat) ([]byte, error) {
+	return JSONEncoder{Format: format}.Marshal(err)
+}
+
+// Marshal encodes the given error into JSON. Errors are encoded as objects keyed by field names, map or slice
+// keys, and ErrorLists as arrays, recursively. A nil error is encoded as null.
+//
+// With JSONStructured, every other error is encoded as an object with the following members: "code" is the
+// error code, omitted if the error does not implement Error; "message" is the error message; "params" holds
+// the template parameters of the error, omitted if there are none; "severity" is "warning" for a warning,
+// and is omitted otherwise. Errors wrapping an Error are recognized using errors.As. An error that implements
+// json.Marshaler and not Error is encoded by its own method, unless it is a warning.
+//
+// With JSONMessages, the result is the same as that of json.Marshal: the warnings found in Errors
+// and ErrorLists are omitted.
+func (e JSONEncoder) Marshal(err error) ([]byte, error) {
+	return json.Marshal(e.encode(err))
+}
+
+// encode returns the value that json.Marshal encodes as the given error.
+func (e JSONEncoder) encode(err error) interface{} {
+	if err == nil {
+		return nil
+	}
+	if e.Format != JSONStructured {
+		if ms, ok := err.(json.Marshaler); ok {
+			return ms
+		}
+		return err.Error()
+	}
+
+	switch errs := err.(type) {
+	case Errors:
+		res := make(map[string]interface{}, len(errs))
+		for key, err := range errs {
+			res[key] = e.encode(err)
+		}
+		return res
+	case ErrorList:
+		res := make([]interface{}, len(errs))
+		for i, err := range errs {
+			res[i] = e.encode(err)
+		}
+		return res
+	}
+
+	severity := ErrorSeverity(err)
+	var ve Error
+	if errors.As(err, &ve) {
+		return jsonError{
+			Code:     ve.Code(),
+			Message:  err.Error(),
+			Params:   ve.Params(),
+			Severity: severity,
+		}
+	}
+	if ms, ok := err.(json.Marshaler); ok && severity == SeverityError {
+		return ms
+	}
+	return jsonError{Message: err.Error(), Severity: severity}
+}
+
+// JSONDecoder decodes validation errors from JSON produced with a given format.
+// The zero value uses JSONMessages.
+type JSONDecoder struct {
+	// Format is the format of the encoded errors.
+	Format JSONFormat
+}
+
+// Unmarshal rebuilds the validation errors encoded as the given JSON and stores them in the value pointed to
+// by v, which must be a *Errors, an *ErrorList, an *ErrorObject or an *error.
+//
+// Objects become Errors, arrays become ErrorLists, null values become nil errors and strings become ErrorObjects
+// without code. With JSONStructured, an object with a "message" string is decoded as an ErrorObject rather than
+// as Errors; please refer to ErrorObject.UnmarshalJSON for more details. With JSONMessages, the errors of a struct
+// with a "message" field, for example, are decoded as Errors as expected.
+func (d JSONDecoder) Unmarshal(data []byte, v interface{}) error {
+	value, err := decodeJSON(data)
+	if err != nil {
+		return err
+	}
+
+	switch v := v.(type) {
+	case *Errors:
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("cannot decode %s into validation.Errors", jsonKind(value))
+		}
+		errs, err := d.decodeErrors(m)
+		if err != nil {
+			return err
+		}
+		*v = errs
+	case *ErrorList:
+		a, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("cannot decode %s into validation.ErrorList", jsonKind(value))
+		}
+		errs, err := d.decodeErrorList(a)
+		if err != nil {
+			return err
+		}
+		*v = errs
+	case *ErrorObject:
+		e, ok := decodeErrorObject(value)
+		if !ok {
+			return fmt.Errorf("cannot decode %s into validation.ErrorObject", jsonKind(value))
+		}
+		*v = e
+	case *error:
+		e, err := d.decode(value)
+		if err != nil {
+			return err
+		}
+		*v = e
+	default:
+		return fmt.Errorf("cannot decode validation errors into %T", v)
+	}
+	return nil
+}
+
+// UnmarshalJSON rebuilds the Errors from their JSONMessages encoding, as produced by json.Marshal.
+// Nested objects become nested Errors, arrays become ErrorLists, null values become nil errors, and messages
+// become ErrorObjects without code. Please use a JSONDecoder to decode the JSONStructured encoding.
+func (es *Errors) UnmarshalJSON(data []byte) error {
+	return JSONDecoder{}.Unmarshal(data, es)
+}
+
+// UnmarshalJSON rebuilds the ErrorList from its JSONMessages encoding, as produced by json.Marshal.
+// Please refer to Errors.UnmarshalJSON for more details.
+func (el *ErrorList) UnmarshalJSON(data []byte) error {
+	return JSONDecoder{}.Unmarshal(data, el)
+}
+
+// UnmarshalJSON rebuilds the ErrorObject from its JSONStructured encoding, i.e. an object with a "code",
+// a "message", "params" and a "severity", or from its JSONMessages encoding, i.e. a message string.
+//
+// As the encoded message is rendered with the params, the decoded message is not a template: Error returns it
+// as is. A decoded error matches, with errors.Is, the error of the same code whose template renders the same
+// message with the decoded params, such as ErrLengthOutOfRange. JSON numbers within the params are decoded as
+// int64 if they are integers, and as float64 otherwise.
+func (e *ErrorObject) UnmarshalJSON(data []byte) error {
+	return JSONDecoder{}.Unmarshal(data, e)
+}
+
+// decodeJSON decodes the given JSON, keeping the precision of the numbers.
+func decodeJSON(data []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// decode rebuilds the error encoded as the given decoded JSON value.
+func (d JSONDecoder) decode(v interface{}) (error, error) {
+	switch v := v.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return ErrorObject{message: v}, nil
+	case map[string]interface{}:
+		if _, ok := v["message"].(string); ok && d.Format == JSONStructured {
+			e, ok := decodeErrorObject(v)
+			if !ok {
+				return nil, errors.New("cannot decode object into validation.ErrorObject")
+			}
+			return e, nil
+		}
+		return d.decodeErrors(v)
+	case []interface{}:
+		return d.decodeErrorList(v)
+	}
+	return nil, fmt.Errorf("cannot decode %s into a validation error", jsonKind(v))
+}
+
+func (d JSONDecoder) decodeErrors(m map[string]interface{}) (Errors, error) {
+	errs := make(Errors, len(m))
+	for key, value := range m {
+		err, decodeErr := d.decode(value)
+		if decodeErr != nil {
+			return nil, decodeErr
+		}
+		errs[key] = err
+	}
+	return errs, nil
+}
+
+func (d JSONDecoder) decodeErrorList(a []interface{}) (ErrorList, error) {
+	errs := make(ErrorList, len(a))
+	for i, value := range a {
+		err, decodeErr := d.decode(value)
+		if decodeErr != nil {
+			return nil, decodeErr
+		}
+		errs[i] = err
+	}
+	return errs, nil
+}
+
+// decodeErrorObject rebuilds the ErrorObject encoded as the given decoded JSON value, either a message string
+// or a JSONStructured object. It returns false if the value does not encode an ErrorObject.
+func decodeErrorObject(v interface{}) (ErrorObject, bool) {
+	switch v := v.(type) {
+	case string:
+		return ErrorObject{message: v}, true
+	case map[string]interface{}:
+		message, ok := v["message"].(string)
+		if !ok {
+			return ErrorObject{}, false
+		}
+		var code string
+		var params map[string]interface{}
+		var severity Severity
+		for key, value := range v {
+			switch key {
+			case "message":
+			case "severity":
+				name, ok := value.(string)
+				if !ok || severity.UnmarshalText([]byte(name)) != nil {
+					return ErrorObject{}, false
+				}
+			case "code":
+				if code, ok = value.(string); !ok {
+					return ErrorObject{}, false
+				}
+			case "params":
+				if value == nil {
+					continue
+				}
+				if params, ok = value.(map[string]interface{}); !ok {
+					return ErrorObject{}, false
+				}
+			default:
+				return ErrorObject{}, false
+			}
+		}
+		if len(params) == 0 {
+			return ErrorObject{code: code, message: message, severity: severity}, true
+		}
+		return ErrorObject{
+			code:     code,
+			message:  escapeTemplate(message),
+			params:   decodeNumbers(params).(map[string]interface{}),
+			severity: severity,
+		}, true
+	}
+	return ErrorObject{}, false
+}
+
+// escapeTemplate returns a message template that renders as the given message.
+func escapeTemplate(message string) string {
+	return strings.ReplaceAll(message, "{{", `{{"{{"}}`)
+}
+
+// decodeNumbers replaces the JSON numbers within the given decoded JSON value with int64 or float64 values.
+func decodeNumbers(v interface{}) interface{} {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}:
+		for key, value := range v {
+			v[key] = decodeNumbers(value)
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = decodeNumbers(value)
+		}
+	}
+	return v
+}
+
+// jsonKind returns the kind of the given decoded JSON value, for error messages.
+func jsonKind(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	}
+	return "object"
+}
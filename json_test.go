@@ -0,0 +1,187 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type jsonMarshalerError struct{}
+
+func (jsonMarshalerError) Error() string { return "custom" }
+
+func (jsonMarshalerError) MarshalJSON() ([]byte, error) {
+	return []byte(`{"custom":true}`), nil
+}
+
+func TestJSONEncoder_Marshal(t *testing.T) {
+	errs := Errors{
+		"name": ErrRequired,
+		"age":  ErrMinGreaterEqualThanRequired.SetParams(map[string]interface{}{"threshold": 18}),
+		"address": Errors{
+			"lines": Errors{"0": ErrLengthOutOfRange.SetParams(map[string]interface{}{"min": 1, "max": 10})},
+		},
+		"email":  ErrorList{ErrRequired, errors.New("plain")},
+		"custom": jsonMarshalerError{},
+		"zip":    fmt.Errorf("wrapped: %w", ErrRequired),
+		"nil":    nil,
+	}
+
+	tests := []struct {
+		tag    string
+		format JSONFormat
+		err    error
+		json   string
+	}{
+		{"t1.1", JSONMessages, nil, `null`},
+		{"t1.2", JSONMessages, ErrRequired, `"cannot be blank"`},
+		{"t1.3", JSONMessages, Errors{"name": ErrRequired, "address": Errors{"0": ErrNil}},
+			`{"address":{"0":"must be blank"},"name":"cannot be blank"}`},
+		{"t1.4", JSONMessages, ErrorList{ErrRequired, ErrNil}, `["cannot be blank","must be blank"]`},
+		{"t2.1", JSONStructured, nil, `null`},
+		{"t2.2", JSONStructured, ErrRequired, `{"code":"validation_required","message":"cannot be blank"}`},
+		{"t2.3", JSONStructured, errors.New("plain"), `{"message":"plain"}`},
+		{"t2.4", JSONStructured, errs, `{` +
+			`"address":{"lines":{"0":{"code":"validation_length_out_of_range","message":"the length must be between 1 and 10","params":{"max":10,"min":1}}}},` +
+			`"age":{"code":"validation_min_greater_equal_than_required","message":"must be no less than 18","params":{"threshold":18}},` +
+			`"custom":{"custom":true},` +
+			`"email":[{"code":"validation_required","message":"cannot be blank"},{"message":"plain"}],` +
+			`"name":{"code":"validation_required","message":"cannot be blank"},` +
+			`"nil":null,` +
+			`"zip":{"code":"validation_required","message":"wrapped: cannot be blank"}}`},
+	}
+
+	for _, test := range tests {
+		data, err := JSONEncoder{Format: test.format}.Marshal(test.err)
+		if assert.NoError(t, err, test.tag) {
+			assert.Equal(t, test.json, string(data), test.tag)
+		}
+		data, err = MarshalErrorJSON(test.err, test.format)
+		if assert.NoError(t, err, test.tag) {
+			assert.Equal(t, test.json, string(data), test.tag)
+		}
+	}
+
+	// the default format is the same as that of json.Marshal
+	err := Errors{"name": ErrRequired, "tags": ErrorList{ErrNil}}
+	expected, _ := json.Marshal(err)
+	data, _ := JSONEncoder{}.Marshal(err)
+	assert.Equal(t, string(expected), string(data))
+}
+
+func TestJSONDecoder_Unmarshal(t *testing.T) {
+	original := Errors{
+		"name": ErrRequired,
+		"age":  ErrMinGreaterEqualThanRequired.SetParams(map[string]interface{}{"threshold": 18}),
+		"address": Errors{
+			"lines": Errors{"0": ErrLengthOutOfRange.SetParams(map[string]interface{}{"min": 1, "max": 10})},
+		},
+		"email": ErrorList{ErrRequired, errors.New("plain")},
+		"price": ErrMinGreaterThanRequired.SetParams(map[string]interface{}{"threshold": 1.5}),
+		"tmpl":  NewError("custom", "{{.value}} is invalid").SetParams(map[string]interface{}{"value": "{{x}}"}),
+	}
+
+	for _, format := range []JSONFormat{JSONStructured, JSONMessages} {
+		data, err := MarshalErrorJSON(original, format)
+		assert.NoError(t, err)
+
+		var decoded Errors
+		if assert.NoError(t, JSONDecoder{Format: format}.Unmarshal(data, &decoded)) {
+			assert.Equal(t, original.Error(), decoded.Error())
+		}
+		// decoding is the inverse of encoding
+		data2, err := MarshalErrorJSON(decoded, format)
+		assert.NoError(t, err)
+		assert.JSONEq(t, string(data), string(data2))
+	}
+
+	data, _ := MarshalErrorJSON(original, JSONStructured)
+	var errs Errors
+	assert.NoError(t, JSONDecoder{Format: JSONStructured}.Unmarshal(data, &errs))
+
+	assert.True(t, errors.Is(errs["name"], ErrRequired))
+	assert.False(t, errors.Is(errs["name"], ErrNil))
+	assert.True(t, errors.Is(errs["age"], ErrMinGreaterEqualThanRequired))
+	assert.True(t, errors.Is(errs, ErrLengthOutOfRange))
+	assert.True(t, errors.Is(errs["email"], ErrRequired))
+	assert.Equal(t, map[string]interface{}{"threshold": int64(18)}, errs["age"].(Error).Params())
+	assert.Equal(t, map[string]interface{}{"threshold": 1.5}, errs["price"].(Error).Params())
+	assert.Equal(t, "validation_required", errs["name"].(Error).Code())
+	assert.Equal(t, "{{x}} is invalid", errs["tmpl"].Error())
+	assert.Equal(t, ErrorObject{message: "plain"}, errs["email"].(ErrorList)[1])
+
+	// objects without a message string are nested Errors
+	structured := JSONDecoder{Format: JSONStructured}
+	assert.NoError(t, structured.Unmarshal([]byte(`{"b":{"message":null}}`), &errs))
+	assert.Equal(t, Errors{"b": Errors{"message": nil}}, errs)
+
+	// any error
+	var e error
+	assert.NoError(t, structured.Unmarshal([]byte(`[{"message":"x"},null]`), &e))
+	assert.Equal(t, ErrorList{ErrorObject{message: "x"}, nil}, e)
+	assert.NoError(t, structured.Unmarshal([]byte(`null`), &e))
+	assert.Nil(t, e)
+
+	// invalid JSON
+	assert.EqualError(t, structured.Unmarshal([]byte(`"abc"`), &errs), "cannot decode string into validation.Errors")
+	assert.EqualError(t, structured.Unmarshal([]byte(`{"a":true}`), &errs), "cannot decode boolean into a validation error")
+	assert.EqualError(t, structured.Unmarshal([]byte(`{"a":[1]}`), &errs), "cannot decode number into a validation error")
+	assert.EqualError(t, structured.Unmarshal([]byte(`{"a":{"message":"x","other":"y"}}`), &errs), "cannot decode object into validation.ErrorObject")
+	var str string
+	assert.EqualError(t, structured.Unmarshal([]byte(`{}`), &str), "cannot decode validation errors into *string")
+}
+
+func TestErrors_UnmarshalJSON(t *testing.T) {
+	// the errors of a struct with a message field are not mistaken for an error object
+	original := Errors{"contact": Errors{"message": ErrRequired, "code": ErrNil}}
+	data, err := json.Marshal(original)
+	assert.NoError(t, err)
+
+	var errs Errors
+	if assert.NoError(t, json.Unmarshal(data, &errs)) {
+		assert.Equal(t, Errors{"contact": Errors{
+			"message": ErrorObject{message: "cannot be blank"},
+			"code":    ErrorObject{message: "must be blank"},
+		}}, errs)
+		assert.Equal(t, ErrorObject{message: "cannot be blank"}, FindByPath(errs, "contact.message"))
+	}
+
+	// the same holds for the structured format
+	data, err = MarshalErrorJSON(original, JSONStructured)
+	assert.NoError(t, err)
+	if assert.NoError(t, JSONDecoder{Format: JSONStructured}.Unmarshal(data, &errs)) {
+		assert.Equal(t, original, errs)
+		assert.True(t, errors.Is(FindByPath(errs, "contact.message"), ErrRequired))
+	}
+
+	assert.EqualError(t, json.Unmarshal([]byte(`"abc"`), &errs), "cannot decode string into validation.Errors")
+}
+
+func TestErrorList_UnmarshalJSON(t *testing.T) {
+	var el ErrorList
+	assert.NoError(t, json.Unmarshal([]byte(`["cannot be blank",{"0":"plain"},null]`), &el))
+	assert.Equal(t, ErrorList{ErrorObject{message: "cannot be blank"}, Errors{"0": ErrorObject{message: "plain"}}, nil}, el)
+	assert.NoError(t, JSONDecoder{Format: JSONStructured}.Unmarshal([]byte(`[{"code":"validation_required","message":"cannot be blank"},"plain",null]`), &el))
+	assert.Equal(t, ErrorList{ErrRequired, ErrorObject{message: "plain"}, nil}, el)
+	assert.EqualError(t, json.Unmarshal([]byte(`{}`), &el), "cannot decode object into validation.ErrorList")
+}
+
+func TestErrorObject_UnmarshalJSON(t *testing.T) {
+	var e ErrorObject
+	assert.NoError(t, json.Unmarshal([]byte(`{"code":"validation_length_out_of_range","message":"the length must be between 1 and 10","params":{"min":1,"max":10}}`), &e))
+	assert.Equal(t, "validation_length_out_of_range", e.Code())
+	assert.Equal(t, "the length must be between 1 and 10", e.Error())
+	assert.True(t, errors.Is(e, ErrLengthOutOfRange))
+	assert.True(t, errors.Is(e, ErrLengthOutOfRange.SetParams(map[string]interface{}{"min": int64(1), "max": int64(10)})))
+	assert.False(t, errors.Is(e, ErrLengthOutOfRange.SetParams(map[string]interface{}{"min": int64(2), "max": int64(10)})))
+	assert.False(t, errors.Is(e, ErrLengthTooLong))
+
+	assert.NoError(t, json.Unmarshal([]byte(`"cannot be blank"`), &e))
+	assert.Equal(t, ErrorObject{message: "cannot be blank"}, e)
+
+	assert.EqualError(t, json.Unmarshal([]byte(`{"code":1,"message":"x"}`), &e), "cannot decode object into validation.ErrorObject")
+	assert.Error(t, json.Unmarshal([]byte(`{`), &e))
+}
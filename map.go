@@ -101,6 +101,10 @@ func (r MapRule) ValidateWithContext(ctx context.Context, m interface{}) error {
 	}
 
 	for _, kr := range r.distinctKeys {
+		if err := contextError(ctx); err != nil {
+			return err
+		}
+
 		var err error
 		if kv := reflect.ValueOf(kr.key); !kt.AssignableTo(kv.Type()) {
 			err = ErrKeyWrongType
@@ -108,19 +112,12 @@ func (r MapRule) ValidateWithContext(ctx context.Context, m interface{}) error {
 			if !kr.optional {
 				err = ErrKeyMissing
 			}
-		} else if ctx == nil {
-			if r.keys != nil {
-				err = Validate(kr.key, r.keys...)
-			}
-			if err == nil {
-				err = Validate(vv.Interface(), append(r.values, kr.rules...)...)
-			}
 		} else {
 			if r.keys != nil {
-				err = ValidateWithContext(ctx, kr.key, r.keys...)
+				err = validateNested(ctx, kr.key, r.keys...)
 			}
 			if err == nil {
-				err = ValidateWithContext(ctx, vv.Interface(), append(r.values, kr.rules...)...)
+				err = validateNested(ctx, vv.Interface(), append(r.values, kr.rules...)...)
 			}
 		}
 		if err != nil {
@@ -129,6 +126,9 @@ func (r MapRule) ValidateWithContext(ctx context.Context, m interface{}) error {
 			}
 
 			errs[getErrorKeyName(kr.key)] = err
+			if stopOnFirstError(ctx) && !IsWarning(err) {
+				return errs
+			}
 		}
 
 		if !r.allowExtraKeys {
@@ -138,6 +138,10 @@ func (r MapRule) ValidateWithContext(ctx context.Context, m interface{}) error {
 
 	if !r.allowExtraKeys || len(r.keys) != 0 || len(r.values) != 0 {
 		for _, kv := range value.MapKeys() {
+			if err := contextError(ctx); err != nil {
+				return err
+			}
+
 			key := kv.Interface()
 
 			if _, ok := visited[key]; ok {
@@ -146,25 +150,20 @@ func (r MapRule) ValidateWithContext(ctx context.Context, m interface{}) error {
 
 			if !r.allowExtraKeys {
 				errs[getErrorKeyName(key)] = ErrKeyUnexpected
+				if stopOnFirstError(ctx) {
+					return errs
+				}
 				continue
 			}
 
 			var err error
 			if len(r.keys) != 0 {
-				if ctx == nil {
-					err = Validate(key, r.keys...)
-				} else {
-					err = ValidateWithContext(ctx, key, r.keys...)
-				}
+				err = validateNested(ctx, key, r.keys...)
 			}
 
 			if err == nil && len(r.values) != 0 {
 				vv := value.MapIndex(kv)
-				if ctx == nil {
-					err = Validate(vv.Interface(), r.values...)
-				} else {
-					err = ValidateWithContext(ctx, vv.Interface(), r.values...)
-				}
+				err = validateNested(ctx, vv.Interface(), r.values...)
 			}
 
 			if err != nil {
@@ -173,6 +172,9 @@ func (r MapRule) ValidateWithContext(ctx context.Context, m interface{}) error {
 				}
 
 				errs[getErrorKeyName(key)] = err
+				if stopOnFirstError(ctx) && !IsWarning(err) {
+					return errs
+				}
 			}
 		}
 	}
@@ -0,0 +1,103 @@
+// Copyright 2016 Qiang Xue, 2022 Jellydator. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import "context"
+
+type stopOnFirstErrorKey struct{}
+
+// WithStopOnFirstError returns a copy of ctx that makes the context-aware validation functions return
+// as soon as the first error is found, instead of validating every struct field, map key or element.
+// This is useful when only a yes/no answer is needed. The returned Errors then contain a single error.
+//
+// The option is honored by ValidateStructWithContext, ValidateWithContext when it walks maps and slices
+// of validatables, and by the ValidateWithContext methods of MapRule, EachRule, ValuesRule and AllRule.
+func WithStopOnFirstError(ctx context.Context) context.Context {
+	return context.WithValue(ctx, stopOnFirstErrorKey{}, true)
+}
+
+// stopOnFirstError checks if ctx was created by WithStopOnFirstError.
+func stopOnFirstError(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	stop, _ := ctx.Value(stopOnFirstErrorKey{}).(bool)
+	return stop
+}
+
+// contextError returns an InternalError wrapping ctx.Err() if ctx is canceled or its deadline is exceeded.
+// The context-aware validation functions check it between struct fields, map keys and elements, so that
+// errors.Is(err, context.Canceled) or errors.Is(err, context.DeadlineExceeded) tells why the validation stopped.
+func contextError(ctx context.Context) error {
+	if ctx == nil {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return NewInternalError(err)
+	}
+	return nil
+}
+
+type warningsKey struct{}
+
+// warningsSink is the sink of the warnings set by WithWarnings. nested tells if the sink is carried by the context
+// of a validation nested in another one, which reports the warnings instead.
+type warningsSink struct {
+	warnings *error
+	nested   bool
+}
+
+// WithWarnings returns a copy of ctx that makes the context-aware validation functions store the warnings
+// they find in *warnings. As warnings do not make a value invalid, these functions do not return them.
+// For example,
+//
+//	var warnings error
+//	err := validation.ValidateWithContext(validation.WithWarnings(ctx, &warnings), password,
+//	    validation.Required,
+//	    validation.Warn(validation.Length(12, 0).Error("is weak")),
+//	)
+//
+// The warnings keep the structure of the errors, i.e. those of struct fields are reported in Errors.
+// If *warnings already holds warnings, the new ones are added to them in an ErrorList. *warnings is left
+// untouched if no warnings are found. The sink should not be shared by concurrent validations.
+//
+// The warnings are also collected from the values validated by their ValidateWithContext method with the given
+// context, but not from those validated by their Validate method, which is called without a context.
+func WithWarnings(ctx context.Context, warnings *error) context.Context {
+	return context.WithValue(ctx, warningsKey{}, warningsSink{warnings: warnings})
+}
+
+// getWarningsSink returns the sink set by WithWarnings, if any.
+func getWarningsSink(ctx context.Context) warningsSink {
+	if ctx == nil {
+		return warningsSink{}
+	}
+	sink, _ := ctx.Value(warningsKey{}).(warningsSink)
+	return sink
+}
+
+type parallelismKey struct{}
+
+// WithParallelism returns a copy of ctx that makes ValidateWithContext validate the elements of maps, slices
+// and arrays of ValidatableWithContext values concurrently, using at most the given number of goroutines.
+// If workers is zero or negative, runtime.GOMAXPROCS(0) goroutines are used.
+//
+// The returned errors are the same as those of a sequential validation. If ctx is canceled, the validation
+// stops and returns an InternalError wrapping ctx.Err(). Please refer to EachParallel to validate the elements
+// of an iterable concurrently with a list of rules.
+func WithParallelism(ctx context.Context, workers int) context.Context {
+	return context.WithValue(ctx, parallelismKey{}, normalizeWorkers(workers))
+}
+
+// parallelism returns the number of goroutines set by WithParallelism, or 1 if there is none.
+func parallelism(ctx context.Context) int {
+	if ctx == nil {
+		return 1
+	}
+	if workers, ok := ctx.Value(parallelismKey{}).(int); ok {
+		return workers
+	}
+	return 1
+}
@@ -0,0 +1,253 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithStopOnFirstError(t *testing.T) {
+	assert.False(t, stopOnFirstError(nil))
+	assert.False(t, stopOnFirstError(context.Background()))
+	assert.True(t, stopOnFirstError(WithStopOnFirstError(context.Background())))
+
+	m := Model1{}
+	mm := map[string]interface{}{"A": "", "B": "", "C": ""}
+	tests := []struct {
+		tag      string
+		validate func(ctx context.Context) error
+		all      string
+		first    string
+	}{
+		{"struct", func(ctx context.Context) error {
+			return ValidateStructWithContext(ctx, &m, Field(&m.A, Required), Field(&m.B, Required))
+		}, "A: cannot be blank; B: cannot be blank.", "A: cannot be blank."},
+		{"each slice", func(ctx context.Context) error {
+			return ValidateWithContext(ctx, []string{"a", "", ""}, Each(Required))
+		}, "1: cannot be blank; 2: cannot be blank.", "1: cannot be blank."},
+		{"each map", func(ctx context.Context) error {
+			return ValidateWithContext(ctx, map[string]string{"a": ""}, Each(Required))
+		}, "a: cannot be blank.", "a: cannot be blank."},
+		{"map", func(ctx context.Context) error {
+			return ValidateWithContext(ctx, mm, Map(Key("A", Required), Key("B", Required)).AllowExtraKeys())
+		}, "A: cannot be blank; B: cannot be blank.", "A: cannot be blank."},
+		{"map extra keys", func(ctx context.Context) error {
+			return ValidateWithContext(ctx, mm, Map(Key("A", Length(0, 1)), Key("B", Length(0, 1))))
+		}, "C: key not expected.", "C: key not expected."},
+		{"validatable slice", func(ctx context.Context) error {
+			return ValidateWithContext(ctx, []Model4{{A: "x"}, {A: "abc"}, {A: "y"}})
+		}, "0: (A: error abc.); 2: (A: error abc.).", "0: (A: error abc.)."},
+		{"validatable map", func(ctx context.Context) error {
+			return ValidateWithContext(ctx, map[string]Model4{"a": {A: "x"}})
+		}, "a: (A: error abc.).", "a: (A: error abc.)."},
+		{"values", func(ctx context.Context) error {
+			return ValidateWithContext(ctx, url.Values{"a": {""}, "b": {""}}, Values(ValueKey("a", Required)))
+		}, "a: cannot be blank; b: key not expected.", "a: cannot be blank."},
+		{"all", func(ctx context.Context) error {
+			return ValidateWithContext(ctx, "abc", All(Length(8, 0), In("x")))
+		}, "the length must be no less than 8; must be a valid value", "the length must be no less than 8"},
+	}
+	for _, test := range tests {
+		assertError(t, test.all, test.validate(context.Background()), test.tag)
+		assertError(t, test.first, test.validate(WithStopOnFirstError(context.Background())), test.tag)
+	}
+
+	// the option is honored by nested structs
+	m5 := Model5{M4: Model4{A: "x"}}
+	err := ValidateWithContext(WithStopOnFirstError(context.Background()), m5)
+	assertError(t, "A: error abc.", err, "nested")
+}
+
+type benchStruct struct {
+	A, B, C, D, E, F, G, H, I, J, K, L, M, N, O, P string
+}
+
+func (s *benchStruct) validate(ctx context.Context) error {
+	return ValidateStructWithContext(ctx, s,
+		Field(&s.A, Required, Length(1, 10)),
+		Field(&s.B, Required, Length(1, 10)),
+		Field(&s.C, Required, Length(1, 10)),
+		Field(&s.D, Required, Length(1, 10)),
+		Field(&s.E, Required, Length(1, 10)),
+		Field(&s.F, Required, Length(1, 10)),
+		Field(&s.G, Required, Length(1, 10)),
+		Field(&s.H, Required, Length(1, 10)),
+		Field(&s.I, Required, Length(1, 10)),
+		Field(&s.J, Required, Length(1, 10)),
+		Field(&s.K, Required, Length(1, 10)),
+		Field(&s.L, Required, Length(1, 10)),
+		Field(&s.M, Required, Length(1, 10)),
+		Field(&s.N, Required, Length(1, 10)),
+		Field(&s.O, Required, Length(1, 10)),
+		Field(&s.P, Required, Length(1, 10)),
+	)
+}
+
+func BenchmarkValidateStructWithContext(b *testing.B) {
+	for _, bench := range []struct {
+		name string
+		ctx  context.Context
+	}{
+		{"All", context.Background()},
+		{"StopOnFirstError", WithStopOnFirstError(context.Background())},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			s := &benchStruct{}
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = s.validate(bench.ctx)
+			}
+		})
+	}
+}
+
+func BenchmarkEachRule(b *testing.B) {
+	values := make([]string, 1000)
+	rule := Each(Required, Length(1, 10))
+	for _, bench := range []struct {
+		name string
+		ctx  context.Context
+	}{
+		{"All", context.Background()},
+		{"StopOnFirstError", WithStopOnFirstError(context.Background())},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = rule.ValidateWithContext(bench.ctx, values)
+			}
+		})
+	}
+}
+
+func TestWithParallelism(t *testing.T) {
+	assert.Equal(t, 1, parallelism(nil))
+	assert.Equal(t, 1, parallelism(context.Background()))
+	assert.Equal(t, 4, parallelism(WithParallelism(context.Background(), 4)))
+	assert.Equal(t, runtime.GOMAXPROCS(0), parallelism(WithParallelism(context.Background(), 0)))
+
+	models := make([]Model4, 1000)
+	m := map[int]*Model4{}
+	for i := range models {
+		if i%7 != 3 {
+			models[i].A = "abc"
+		}
+		m[i] = &models[i]
+	}
+
+	for _, value := range []interface{}{models, m} {
+		for _, ctx := range []context.Context{context.Background(), WithStopOnFirstError(context.Background())} {
+			expected := ValidateWithContext(ctx, value)
+			err := ValidateWithContext(WithParallelism(ctx, 8), value)
+			if _, ok := value.(map[int]*Model4); ok && stopOnFirstError(ctx) {
+				// the first failing key of a map is not deterministic
+				assert.Len(t, err, 1)
+				continue
+			}
+			assert.Equal(t, expected, err)
+		}
+	}
+
+	// cancellation
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := ValidateWithContext(WithParallelism(ctx, 8), models)
+	if assert.Implements(t, (*InternalError)(nil), err) {
+		assert.Equal(t, context.Canceled, err.(InternalError).InternalError())
+	}
+}
+
+func BenchmarkWithParallelism(b *testing.B) {
+	models := make([]Model4, 1000)
+	for _, workers := range []int{1, 4, 16} {
+		b.Run(strconv.Itoa(workers), func(b *testing.B) {
+			ctx := WithParallelism(context.Background(), workers)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = ValidateWithContext(ctx, models)
+			}
+		})
+	}
+}
+
+func TestContextCancellation(t *testing.T) {
+	// cancelAfter returns a rule that cancels the context after validating n values, and the number of validated values.
+	cancelAfter := func(n int) (Rule, context.Context, *int) {
+		ctx, cancel := context.WithCancel(context.Background())
+		count := 0
+		return By(func(interface{}) error {
+			count++
+			if count == n {
+				cancel()
+			}
+			return nil
+		}), ctx, &count
+	}
+
+	values := make([]string, 10)
+	m := Model1{}
+	tests := []struct {
+		tag      string
+		validate func(ctx context.Context, rule Rule) error
+	}{
+		{"each", func(ctx context.Context, rule Rule) error {
+			return ValidateWithContext(ctx, values, Each(rule))
+		}},
+		{"each map", func(ctx context.Context, rule Rule) error {
+			return ValidateWithContext(ctx, map[int]string{1: "", 2: "", 3: "", 4: ""}, Each(rule))
+		}},
+		{"struct", func(ctx context.Context, rule Rule) error {
+			return ValidateStructWithContext(ctx, &m, Field(&m.A, rule), Field(&m.B, rule), Field(&m.G, rule))
+		}},
+		{"map", func(ctx context.Context, rule Rule) error {
+			return ValidateWithContext(ctx, map[string]string{"a": "", "b": "", "c": ""},
+				Map(Key("a", rule), Key("b", rule)).Values(rule).AllowExtraKeys())
+		}},
+		{"values", func(ctx context.Context, rule Rule) error {
+			return ValidateWithContext(ctx, url.Values{"a": {""}, "b": {""}, "c": {""}},
+				Values(ValueKey("a", rule), ValueKey("b", rule), ValueKey("c", rule)))
+		}},
+		{"nested", func(ctx context.Context, rule Rule) error {
+			return ValidateWithContext(ctx, [][]string{{"a", "b"}, {"c", "d"}}, Each(Each(rule)))
+		}},
+	}
+	for _, test := range tests {
+		rule, ctx, count := cancelAfter(2)
+		err := test.validate(ctx, rule)
+		assert.Equal(t, 2, *count, test.tag)
+		if assert.Implements(t, (*InternalError)(nil), err, test.tag) {
+			assert.True(t, errors.Is(err, context.Canceled), test.tag)
+		}
+	}
+
+	// validatable slices and maps
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, value := range []interface{}{[]Model4{{A: "x"}}, map[string]Model4{"a": {A: "x"}}} {
+		err := ValidateWithContext(ctx, value)
+		assert.True(t, errors.Is(err, context.Canceled))
+	}
+
+	// timeout in the middle of a slice
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	count := 0
+	err := ValidateWithContext(ctx, make([]int, 1000), Each(By(func(interface{}) error {
+		count++
+		time.Sleep(5 * time.Millisecond)
+		return nil
+	})))
+	if assert.Implements(t, (*InternalError)(nil), err) {
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	}
+	assert.Less(t, count, 1000)
+
+	// no context
+	assert.NoError(t, Validate(values, Each(Length(0, 1))))
+}
@@ -0,0 +1,112 @@
+// Copyright 2016 Qiang Xue, 2022 Jellydator. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// normalizeWorkers returns the number of goroutines to use for the given number of workers.
+func normalizeWorkers(workers int) int {
+	if workers <= 0 {
+		return runtime.GOMAXPROCS(0)
+	}
+	return workers
+}
+
+// validateParallel validates the elements identified by the given keys using at most the given number
+// of goroutines. The i-th element is validated by calling validate(i).
+//
+// The errors are assembled in the same way as a sequential validation would do: the returned Errors are keyed
+// by the keys of the failing elements and, when ctx was created by WithStopOnFirstError, contain only the error
+// of the first failing element in the order of the keys, along with the warnings of the elements preceding it.
+// The first InternalError in the order of the keys is returned as is, and the elements following an element that
+// returned an InternalError are not validated. If ctx is canceled, an InternalError wrapping ctx.Err() is returned.
+// A panic in validate is propagated to the caller.
+func validateParallel(ctx context.Context, workers int, keys []string, validate func(i int) error) error {
+	n := len(keys)
+	if workers > n {
+		workers = n
+	}
+	stop := stopOnFirstError(ctx)
+	var done <-chan struct{}
+	if ctx != nil {
+		done = ctx.Done()
+	}
+
+	results := make([]error, n)
+	var (
+		next     atomic.Int64
+		failed   atomic.Int64
+		panicked atomic.Value
+		wg       sync.WaitGroup
+	)
+	failed.Store(int64(n))
+
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			defer func() {
+				if p := recover(); p != nil {
+					panicked.CompareAndSwap(nil, &p)
+					failed.Store(-1)
+				}
+			}()
+			for {
+				// elements are taken in order, so the remaining ones follow any failing element found so far
+				i := next.Add(1) - 1
+				if i >= int64(n) || i > failed.Load() {
+					return
+				}
+				select {
+				case <-done:
+					return
+				default:
+				}
+				if err := validate(int(i)); err != nil {
+					results[i] = err
+					// an internal error is returned as is, so the elements following it need not be validated
+					ie, internal := err.(InternalError)
+					internal = internal && ie.InternalError() != nil
+					for internal || stop && !IsWarning(err) {
+						f := failed.Load()
+						if i >= f || failed.CompareAndSwap(f, i) {
+							break
+						}
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if p := panicked.Load(); p != nil {
+		panic(*p.(*interface{}))
+	}
+	if ctx != nil && ctx.Err() != nil {
+		return NewInternalError(ctx.Err())
+	}
+
+	errs := Errors{}
+	for i, err := range results {
+		if err != nil {
+			if ie, ok := err.(InternalError); ok && ie.InternalError() != nil {
+				return err
+			}
+			errs[keys[i]] = err
+			if stop && !IsWarning(err) {
+				break
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+// Copyright 2016 Qiang Xue, 2022 Jellydator. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package problem renders validation errors as RFC 9457 (formerly RFC 7807) problem details,
+// i.e. as "application/problem+json" documents.
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/jellydator/validation"
+)
+
+// ContentType is the media type of problem details documents.
+const ContentType = "application/problem+json"
+
+// DefaultMember is the name of the extension member that lists the invalid params.
+const DefaultMember = "invalid-params"
+
+// DefaultWarningsMember is the name of the extension member that lists the warnings.
+const DefaultWarningsMember = "warnings"
+
+type (
+	// Problem is a problem details document.
+	Problem struct {
+		// Type is a URI reference that identifies the problem type. "about:blank" is assumed if empty.
+		Type string `json:"type,omitempty"`
+		// Title is a short summary of the problem type.
+		Title string `json:"title,omitempty"`
+		// Status is the HTTP status code.
+		Status int `json:"status,omitempty"`
+		// Detail is an explanation specific to this occurrence of the problem.
+		Detail string `json:"detail,omitempty"`
+		// Instance is a URI reference that identifies this occurrence of the problem.
+		Instance string `json:"instance,omitempty"`
+		// InvalidParams lists the validation errors. It is rendered as the extension member
+		// named by Converter.Member, or DefaultMember.
+		InvalidParams []InvalidParam `json:"-"`
+		// Warnings lists the validation warnings found along with the errors, i.e. the errors of
+		// validation.SeverityWarning. It is rendered as the extension member named by Converter.WarningsMember,
+		// or DefaultWarningsMember.
+		Warnings []InvalidParam `json:"-"`
+		// Extensions holds additional members that are rendered next to the standard ones.
+		Extensions map[string]interface{} `json:"-"`
+
+		member         string
+		warningsMember string
+	}
+
+	// InvalidParam describes a single validation error.
+	InvalidParam struct {
+		// Name is the path of the invalid field, rendered using Converter.PathSyntax.
+		// It is empty if the validated value itself is invalid.
+		Name string `json:"name"`
+		// Reason is the error message.
+		Reason string `json:"reason"`
+		// Code is the error code, if any.
+		Code string `json:"code,omitempty"`
+		// Type is the URI reference returned by Converter.TypeURI for the error code, if any.
+		Type string `json:"type,omitempty"`
+		// Params are the error's template parameters, if any.
+		Params map[string]interface{} `json:"params,omitempty"`
+	}
+
+	// Converter converts errors returned by the validation functions into problem details.
+	// The zero value is ready to use.
+	Converter struct {
+		// Type is the problem type URI of validation failures. "about:blank" is used if empty.
+		Type string
+		// Status is the HTTP status code of validation failures. http.StatusUnprocessableEntity is used if zero.
+		Status int
+		// PathSyntax determines how the names of the invalid params are rendered.
+		PathSyntax validation.PathSyntax
+		// Member is the name of the extension member that lists the invalid params, e.g. "errors".
+		// DefaultMember is used if empty.
+		Member string
+		// WarningsMember is the name of the extension member that lists the warnings.
+		// DefaultWarningsMember is used if empty.
+		WarningsMember string
+		// TypeURI returns the URI reference that identifies the given error code, or an empty string.
+		TypeURI func(code string) string
+	}
+)
+
+// DefaultConverter is the Converter used by New and Write.
+var DefaultConverter = &Converter{}
+
+// New converts the given error into a Problem using DefaultConverter.
+// Please refer to Converter.New for more details.
+func New(err error) *Problem {
+	return DefaultConverter.New(err)
+}
+
+// Write writes the given error as a problem details document using DefaultConverter.
+// Please refer to Converter.Write for more details.
+func Write(w http.ResponseWriter, err error) error {
+	return DefaultConverter.Write(w, err)
+}
+
+// New converts the given error into a Problem. It returns nil if err is nil or only holds warnings,
+// as reported by validation.Warn, since those do not make the value invalid.
+//
+// An InternalError results in a 500 Internal Server Error problem which doesn't expose the error.
+// Any other error is treated as a validation failure: the problem lists each error found by
+// validation.Flatten as an invalid param, along with its code and params. The warnings found along
+// with the errors are listed apart, in the same form.
+func (c *Converter) New(err error) *Problem {
+	if err == nil {
+		return nil
+	}
+
+	var ie validation.InternalError
+	if errors.As(err, &ie) {
+		return &Problem{
+			Title:  http.StatusText(http.StatusInternalServerError),
+			Status: http.StatusInternalServerError,
+			member: c.Member,
+		}
+	}
+
+	errs, warnings := validation.SplitWarnings(err)
+	if errs == nil {
+		return nil
+	}
+
+	status := c.Status
+	if status == 0 {
+		status = http.StatusUnprocessableEntity
+	}
+	return &Problem{
+		Type:           c.Type,
+		Title:          http.StatusText(status),
+		Status:         status,
+		InvalidParams:  c.params(errs),
+		Warnings:       c.params(warnings),
+		member:         c.Member,
+		warningsMember: c.WarningsMember,
+	}
+}
+
+// params lists each error found by validation.Flatten as an invalid param.
+func (c *Converter) params(err error) []InvalidParam {
+	var params []InvalidParam
+	for _, fe := range validation.Flatten(err, c.PathSyntax) {
+		param := InvalidParam{
+			Name:   fe.Path,
+			Reason: fe.Message,
+			Code:   fe.Code,
+			Params: fe.Params,
+		}
+		if c.TypeURI != nil && fe.Code != "" {
+			param.Type = c.TypeURI(fe.Code)
+		}
+		params = append(params, param)
+	}
+	return params
+}
+
+// Write writes the given error as a problem details document with the status code of the problem.
+// Nothing is written if err is nil or only holds warnings.
+func (c *Converter) Write(w http.ResponseWriter, err error) error {
+	p := c.New(err)
+	if p == nil {
+		return nil
+	}
+	return p.Write(w)
+}
+
+// Write writes the problem to w with the problem details content type and the status code of the problem.
+func (p *Problem) Write(w http.ResponseWriter) error {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	status := p.Status
+	if status == 0 {
+		status = http.StatusInternalServerError
+	}
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(status)
+	_, err = w.Write(data)
+	return err
+}
+
+// Error returns the title of the problem, so that a Problem can be returned as an error.
+func (p *Problem) Error() string {
+	return p.Title
+}
+
+// MarshalJSON renders the standard members of the problem along with the invalid params, the warnings
+// and the extensions.
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	type standard Problem
+	data, err := json.Marshal((*standard)(p))
+	if err != nil || (len(p.InvalidParams) == 0 && len(p.Warnings) == 0 && len(p.Extensions) == 0) {
+		return data, err
+	}
+
+	members := map[string]interface{}{}
+	if err := json.Unmarshal(data, &members); err != nil {
+		return nil, err
+	}
+	for name, value := range p.Extensions {
+		if _, ok := members[name]; !ok {
+			members[name] = value
+		}
+	}
+	if len(p.InvalidParams) > 0 {
+		member := p.member
+		if member == "" {
+			member = DefaultMember
+		}
+		members[member] = p.InvalidParams
+	}
+	if len(p.Warnings) > 0 {
+		member := p.warningsMember
+		if member == "" {
+			member = DefaultWarningsMember
+		}
+		members[member] = p.Warnings
+	}
+	return json.Marshal(members)
+}
@@ -0,0 +1,142 @@
+// Copyright 2016 Qiang Xue, 2022 Jellydator. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jellydator/validation"
+	"github.com/stretchr/testify/assert"
+)
+
+type address struct {
+	Street string
+	Lines  []string
+}
+
+type customer struct {
+	Name    string `json:"name"`
+	Address address
+}
+
+func (c customer) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Name, validation.Required, validation.Length(2, 5)),
+		validation.Field(&c.Address),
+	)
+}
+
+func (a address) Validate() error {
+	return validation.ValidateStruct(&a,
+		validation.Field(&a.Street, validation.Required),
+		validation.Field(&a.Lines, validation.Each(validation.Length(0, 3))),
+	)
+}
+
+func TestConverter_New(t *testing.T) {
+	assert.Nil(t, New(nil))
+
+	err := customer{Name: "abcdef", Address: address{Lines: []string{"ab", "abcd"}}}.Validate()
+	p := New(err)
+	assert.Equal(t, "", p.Type)
+	assert.Equal(t, "Unprocessable Entity", p.Title)
+	assert.Equal(t, http.StatusUnprocessableEntity, p.Status)
+	assert.Equal(t, []InvalidParam{
+		{Name: "Address.Lines[1]", Reason: "the length must be no more than 3", Code: "validation_length_too_long", Params: map[string]interface{}{"min": 0, "max": 3}},
+		{Name: "Address.Street", Reason: "cannot be blank", Code: "validation_required"},
+		{Name: "name", Reason: "the length must be between 2 and 5", Code: "validation_length_out_of_range", Params: map[string]interface{}{"min": 2, "max": 5}},
+	}, p.InvalidParams)
+
+	c := &Converter{
+		Type:       "https://example.com/probs/validation",
+		Status:     http.StatusBadRequest,
+		PathSyntax: validation.PathJSONPointer,
+		TypeURI: func(code string) string {
+			if code == "validation_required" {
+				return "https://example.com/probs/required"
+			}
+			return ""
+		},
+	}
+	p = c.New(validation.Errors{"name": validation.ErrRequired, "age": errors.New("too young")})
+	assert.Equal(t, "https://example.com/probs/validation", p.Type)
+	assert.Equal(t, "Bad Request", p.Title)
+	assert.Equal(t, http.StatusBadRequest, p.Status)
+	assert.Equal(t, []InvalidParam{
+		{Name: "/age", Reason: "too young"},
+		{Name: "/name", Reason: "cannot be blank", Code: "validation_required", Type: "https://example.com/probs/required"},
+	}, p.InvalidParams)
+
+	// a single value
+	p = New(validation.Validate("", validation.Required))
+	assert.Equal(t, []InvalidParam{{Reason: "cannot be blank", Code: "validation_required"}}, p.InvalidParams)
+
+	// warnings
+	warn := validation.Warn(validation.Length(8, 0))
+	c = &Converter{PathSyntax: validation.PathJSONPointer}
+	p = c.New(validation.Errors{"password": warn.Validate("secret")})
+	assert.Nil(t, p)
+	p = c.New(validation.Errors{"password": warn.Validate("secret"), "name": validation.ErrRequired})
+	assert.Equal(t, []InvalidParam{{Name: "/name", Reason: "cannot be blank", Code: "validation_required"}}, p.InvalidParams)
+	assert.Equal(t, []InvalidParam{{Name: "/password", Reason: "the length must be no less than 8", Code: "validation_length_too_short", Params: map[string]interface{}{"min": 8, "max": 0}}}, p.Warnings)
+
+	// internal errors
+	p = New(validation.NewInternalError(errors.New("db is down")))
+	assert.Equal(t, &Problem{Title: "Internal Server Error", Status: http.StatusInternalServerError}, p)
+	assert.Equal(t, "Internal Server Error", p.Error())
+}
+
+func TestProblem_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		tag       string
+		converter *Converter
+		err       error
+		expected  string
+	}{
+		{"t1", &Converter{}, validation.Errors{"name": validation.ErrRequired}, `{"invalid-params":[{"name":"name","reason":"cannot be blank","code":"validation_required"}],"status":422,"title":"Unprocessable Entity"}`},
+		{"t2", &Converter{Member: "errors", PathSyntax: validation.PathJSONPath}, validation.Errors{"name": validation.ErrRequired}, `{"errors":[{"name":"$.name","reason":"cannot be blank","code":"validation_required"}],"status":422,"title":"Unprocessable Entity"}`},
+		{"t3", &Converter{}, validation.NewInternalError(errors.New("x")), `{"title":"Internal Server Error","status":500}`},
+		{"t4", &Converter{WarningsMember: "notes"}, validation.Errors{"name": validation.ErrRequired, "nick": validation.ErrNil.(validation.ErrorObject).SetSeverity(validation.SeverityWarning)}, `{"invalid-params":[{"name":"name","reason":"cannot be blank","code":"validation_required"}],"notes":[{"name":"nick","reason":"must be blank","code":"validation_nil"}],"status":422,"title":"Unprocessable Entity"}`},
+		{"t5", &Converter{}, validation.ErrorList{validation.ErrRequired, validation.ErrNil.(validation.ErrorObject).SetSeverity(validation.SeverityWarning)}, `{"invalid-params":[{"name":"","reason":"cannot be blank","code":"validation_required"}],"status":422,"title":"Unprocessable Entity","warnings":[{"name":"","reason":"must be blank","code":"validation_nil"}]}`},
+	}
+	for _, test := range tests {
+		data, err := json.Marshal(test.converter.New(test.err))
+		assert.Nil(t, err, test.tag)
+		assert.Equal(t, test.expected, string(data), test.tag)
+	}
+
+	p := &Problem{Title: "Not Found", Status: http.StatusNotFound, Extensions: map[string]interface{}{"balance": 30, "title": "ignored"}}
+	data, err := json.Marshal(p)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"balance":30,"status":404,"title":"Not Found"}`, string(data))
+}
+
+func TestWrite(t *testing.T) {
+	w := httptest.NewRecorder()
+	assert.Nil(t, Write(w, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Body.String())
+
+	w = httptest.NewRecorder()
+	assert.Nil(t, Write(w, validation.Errors{"name": validation.ErrRequired}))
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, ContentType, w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"title":"Unprocessable Entity","status":422,"invalid-params":[{"name":"name","reason":"cannot be blank","code":"validation_required"}]}`, w.Body.String())
+
+	// warnings alone are not written
+	w = httptest.NewRecorder()
+	assert.Nil(t, Write(w, validation.Validate("abc", validation.Warn(validation.Length(5, 0)))))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Body.String())
+
+	w = httptest.NewRecorder()
+	assert.Nil(t, Write(w, validation.NewInternalError(errors.New("x"))))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.JSONEq(t, `{"title":"Internal Server Error","status":500}`, w.Body.String())
+}
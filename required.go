@@ -4,6 +4,8 @@
 
 package validation
 
+import "context"
+
 var (
 	// ErrRequired is the error that returns when a value is required.
 	ErrRequired = NewError("validation_required", "cannot be blank")
@@ -27,13 +29,19 @@ var NilOrNotEmpty = RequiredRule{skipNil: true, condition: true}
 // RequiredRule is a rule that checks if a value is not empty.
 type RequiredRule struct {
 	condition bool
+	lazy      *lazyCondition
 	skipNil   bool
 	err       Error
 }
 
 // Validate checks if the given value is valid or not.
 func (r RequiredRule) Validate(value interface{}) error {
-	if r.condition {
+	return r.ValidateWithContext(nil, value)
+}
+
+// ValidateWithContext checks if the given value is valid or not.
+func (r RequiredRule) ValidateWithContext(ctx context.Context, value interface{}) error {
+	if r.lazy.eval(ctx, value, r.condition) {
 		value, isNil := Indirect(value)
 		if r.skipNil && !isNil && IsEmpty(value) || !r.skipNil && (isNil || IsEmpty(value)) {
 			if r.err != nil {
@@ -51,6 +59,20 @@ func (r RequiredRule) Validate(value interface{}) error {
 // When sets the condition that determines if the validation should be performed.
 func (r RequiredRule) When(condition bool) RequiredRule {
 	r.condition = condition
+	r.lazy = nil
+	return r
+}
+
+// WhenFunc sets a function that determines at validation time if the validation should be performed.
+// The function is called with the validation context, or context.Background() if there is none.
+func (r RequiredRule) WhenFunc(condition func(ctx context.Context) bool) RequiredRule {
+	r.lazy = ctxCondition(condition)
+	return r
+}
+
+// WhenValue sets a predicate that determines if the validation should be performed for the value being validated.
+func (r RequiredRule) WhenValue(predicate func(value interface{}) bool) RequiredRule {
+	r.lazy = valueCondition(predicate)
 	return r
 }
 
@@ -5,6 +5,7 @@
 package validation
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -56,6 +57,42 @@ func TestRequiredRule_When(t *testing.T) {
 	assert.Equal(t, ErrRequired, err)
 }
 
+func TestRequiredRule_WhenFunc(t *testing.T) {
+	r := Required.WhenFunc(func(ctx context.Context) bool {
+		return ctx.Value(contains) != nil
+	})
+	assert.Nil(t, Validate(nil, r))
+	assert.Nil(t, ValidateWithContext(context.Background(), nil, r))
+	assert.Equal(t, ErrRequired, ValidateWithContext(context.WithValue(context.Background(), contains, "abc"), nil, r))
+
+	// When overrides WhenFunc
+	assert.Equal(t, ErrRequired, Validate(nil, r.When(true)))
+}
+
+func TestRequiredRule_WhenValue(t *testing.T) {
+	r := NilOrNotEmpty.WhenValue(func(value interface{}) bool {
+		_, ok := value.(*string)
+		return ok
+	})
+	empty := ""
+	assert.Nil(t, Validate("", r))
+	assert.Equal(t, ErrNilOrNotEmpty, Validate(&empty, r))
+}
+
+func TestRequiredRule_Comparable(t *testing.T) {
+	assert.True(t, Required == Required)
+	assert.False(t, Required == NilOrNotEmpty)
+
+	var r Rule = Required
+	assert.True(t, r == Rule(Required))
+	assert.False(t, r == Rule(Required.When(false)))
+	assert.False(t, r == Rule(Required.WhenFunc(func(context.Context) bool { return true })))
+
+	for _, rule := range []Rule{NilOrNotEmpty, Nil, Empty, Skip} {
+		assert.True(t, rule == rule)
+	}
+}
+
 func TestNilOrNotEmpty(t *testing.T) {
 	s1 := "123"
 	s2 := ""
@@ -0,0 +1,296 @@
+// Copyright 2016 Qiang Xue, 2022 Jellydator. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Severity is the severity of a validation error.
+type Severity int
+
+// Available severities.
+const (
+	// SeverityError is the severity of the errors that make a value invalid. It is the default.
+	SeverityError Severity = iota
+	// SeverityWarning is the severity of the errors that are reported without making a value invalid.
+	SeverityWarning
+)
+
+// String returns the name of the severity, i.e. "error" or "warning".
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+// MarshalText encodes the severity as its name.
+func (s Severity) MarshalText() ([]byte, error) {
+	switch s {
+	case SeverityError, SeverityWarning:
+		return []byte(s.String()), nil
+	}
+	return nil, fmt.Errorf("unknown severity %d", int(s))
+}
+
+// UnmarshalText decodes the severity from its name.
+func (s *Severity) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "error":
+		*s = SeverityError
+	case "warning":
+		*s = SeverityWarning
+	default:
+		return fmt.Errorf("unknown severity %q", text)
+	}
+	return nil
+}
+
+type (
+	// WarnRule is a validation rule that reports the errors of another rule as warnings.
+	WarnRule struct {
+		rule Rule
+	}
+
+	// warningError marks an error that does not implement Error as a warning.
+	warningError struct {
+		error
+	}
+)
+
+// Warn returns a validation rule that reports the errors of the given rule as warnings, i.e. with SeverityWarning.
+// It suits soft checks, such as checking that a password is strong, which should be surfaced to users without
+// rejecting the value. For example,
+//
+//	var warnings error
+//	err := validation.ValidateWithContext(validation.WithWarnings(ctx, &warnings), password,
+//	    validation.Required,
+//	    validation.Warn(validation.Length(12, 0).Error("is weak")),
+//	)
+//
+// Unlike errors, warnings do not stop the validation of a value: Validate and ValidateWithContext apply the rules
+// following a rule that warns. Warnings are not taken into account by WithStopOnFirstError either. They do not make
+// a value invalid: the validation functions, such as ValidateWithContext and ValidateStructWithContext, only return
+// the errors, and store the warnings in the sink set by WithWarnings, if any. Rules, on the other hand, return the
+// warnings as errors, in an ErrorList along with the errors if needed; please use SplitWarnings, IsWarning
+// or Errors.FilterSeverity to tell them apart.
+//
+// Internal errors are not turned into warnings.
+func Warn(rule Rule) WarnRule {
+	return WarnRule{rule: rule}
+}
+
+// Validate checks if the given value is valid or not.
+func (r WarnRule) Validate(value interface{}) error {
+	return r.ValidateWithContext(nil, value)
+}
+
+// ValidateWithContext checks if the given value is valid or not.
+func (r WarnRule) ValidateWithContext(ctx context.Context, value interface{}) error {
+	var err error
+	if rc, ok := r.rule.(RuleWithContext); ok && ctx != nil {
+		err = rc.ValidateWithContext(ctx, value)
+	} else {
+		err = r.rule.Validate(value)
+	}
+	return asWarning(err)
+}
+
+// nestedRules returns the rule whose errors are reported as warnings.
+func (r WarnRule) nestedRules() [][]Rule {
+	return [][]Rule{{r.rule}}
+}
+
+// withNestedRules returns a copy of the rule that reports the errors of the given rule instead.
+func (r WarnRule) withNestedRules(rules [][]Rule) Rule {
+	r.rule = rules[0][0]
+	return r
+}
+
+// asWarning returns a copy of the given error tree whose errors are warnings.
+func asWarning(err error) error {
+	switch e := err.(type) {
+	case nil:
+		return nil
+	case InternalError:
+		if e.InternalError() != nil {
+			return err
+		}
+	case Errors:
+		es := make(Errors, len(e))
+		for key, value := range e {
+			es[key] = asWarning(value)
+		}
+		return es
+	case ErrorList:
+		el := make(ErrorList, len(e))
+		for i, value := range e {
+			el[i] = asWarning(value)
+		}
+		return el
+	case ErrorObject:
+		return e.SetSeverity(SeverityWarning)
+	}
+	return warningError{error: err}
+}
+
+// Severity returns SeverityWarning.
+func (e warningError) Severity() Severity {
+	return SeverityWarning
+}
+
+// Unwrap returns the error marked as a warning.
+func (e warningError) Unwrap() error {
+	return e.error
+}
+
+// ErrorSeverity returns the severity of the given error, which is SeverityError unless the error, or an error
+// it wraps, has a Severity method returning another severity. Errors and ErrorLists are not inspected: please use
+// IsWarning to check if they contain warnings only.
+func ErrorSeverity(err error) Severity {
+	if eo, ok := err.(ErrorObject); ok {
+		return eo.severity
+	}
+	var s interface{ Severity() Severity }
+	if errors.As(err, &s) {
+		return s.Severity()
+	}
+	return SeverityError
+}
+
+// IsWarning checks if the given error is not nil and contains warnings only. Errors and ErrorLists are walked
+// recursively, ignoring nil errors.
+func IsWarning(err error) bool {
+	hasErrors, hasWarnings := containsSeverities(err)
+	return hasWarnings && !hasErrors
+}
+
+// containsSeverities checks if the given error tree contains errors and warnings, respectively.
+// It stops walking the tree as soon as an error is found.
+func containsSeverities(err error) (hasErrors, hasWarnings bool) {
+	switch e := err.(type) {
+	case nil:
+		return false, false
+	case Errors:
+		for _, value := range e {
+			errs, warnings := containsSeverities(value)
+			if errs {
+				return true, hasWarnings || warnings
+			}
+			hasWarnings = hasWarnings || warnings
+		}
+		return false, hasWarnings
+	case ErrorList:
+		for _, value := range e {
+			errs, warnings := containsSeverities(value)
+			if errs {
+				return true, hasWarnings || warnings
+			}
+			hasWarnings = hasWarnings || warnings
+		}
+		return false, hasWarnings
+	}
+	if ErrorSeverity(err) == SeverityWarning {
+		return false, true
+	}
+	return true, false
+}
+
+// SplitWarnings splits the given error tree into the errors and the warnings it contains, keeping the structure
+// of Errors and ErrorLists. Each result is nil if there are no such errors.
+func SplitWarnings(err error) (errs error, warnings error) {
+	if ie, ok := err.(InternalError); ok && ie.InternalError() != nil {
+		return err, nil
+	}
+	return filterSeverity(err, SeverityError), filterSeverity(err, SeverityWarning)
+}
+
+// filterSeverity returns a copy of the given error tree that only contains the errors of the given severities,
+// or nil if there are none.
+func filterSeverity(err error, severities ...Severity) error {
+	switch e := err.(type) {
+	case nil:
+		return nil
+	case Errors:
+		es := Errors{}
+		for key, value := range e {
+			if value = filterSeverity(value, severities...); value != nil {
+				es[key] = value
+			}
+		}
+		if len(es) == 0 {
+			return nil
+		}
+		return es
+	case ErrorList:
+		var el ErrorList
+		for _, value := range e {
+			if value = filterSeverity(value, severities...); value != nil {
+				el = append(el, value)
+			}
+		}
+		return el.Filter()
+	}
+
+	severity := ErrorSeverity(err)
+	for _, s := range severities {
+		if s == severity {
+			return err
+		}
+	}
+	return nil
+}
+
+// withoutWarnings returns a copy of the given error tree without its warnings, or nil if there are only warnings.
+func withoutWarnings(err error) error {
+	if ie, ok := err.(InternalError); ok && ie.InternalError() != nil {
+		return err
+	}
+	return filterSeverity(err, SeverityError)
+}
+
+// reportWarnings calls validate with ctx and returns the errors it finds without the warnings, which are stored
+// in the sink set by WithWarnings, if any. When ctx is the context of a validation nested in another one reporting
+// to the same sink, the warnings are returned along with the errors, so that the outer validation reports them
+// with the path of the nested value.
+func reportWarnings(ctx context.Context, validate func(ctx context.Context) error) error {
+	sink := getWarningsSink(ctx)
+	if sink.nested {
+		return validate(ctx)
+	}
+	if sink.warnings == nil {
+		return withoutWarnings(validate(ctx))
+	}
+
+	errs, warnings := SplitWarnings(validate(context.WithValue(ctx, warningsKey{}, warningsSink{warnings: sink.warnings, nested: true})))
+	if warnings != nil {
+		if *sink.warnings != nil {
+			warnings = ErrorList{*sink.warnings, warnings}
+		}
+		*sink.warnings = warnings
+	}
+	return errs
+}
+
+// joinWarnings returns the warnings reported by the rules of a value along with the validation result
+// of the value, in an ErrorList if needed.
+func joinWarnings(warnings ErrorList, err error) error {
+	if len(warnings) == 0 {
+		return err
+	}
+	if err != nil {
+		if ie, ok := err.(InternalError); ok && ie.InternalError() != nil {
+			return err
+		}
+		warnings = append(warnings, err)
+	}
+	return warnings.Filter()
+}
@@ -0,0 +1,252 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errWeak = NewError("password_weak", "is weak")
+
+func TestSeverity(t *testing.T) {
+	assert.Equal(t, "error", SeverityError.String())
+	assert.Equal(t, "warning", SeverityWarning.String())
+	assert.Equal(t, "Severity(5)", Severity(5).String())
+
+	text, err := SeverityWarning.MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, "warning", string(text))
+	_, err = Severity(5).MarshalText()
+	assert.EqualError(t, err, "unknown severity 5")
+
+	var s Severity
+	assert.NoError(t, s.UnmarshalText([]byte("warning")))
+	assert.Equal(t, SeverityWarning, s)
+	assert.NoError(t, s.UnmarshalText([]byte("error")))
+	assert.Equal(t, SeverityError, s)
+	assert.EqualError(t, s.UnmarshalText([]byte("info")), `unknown severity "info"`)
+}
+
+func TestWarn(t *testing.T) {
+	weak := By(func(value interface{}) error {
+		if len(value.(string)) < 8 {
+			return errWeak
+		}
+		return nil
+	})
+
+	tests := []struct {
+		tag      string
+		value    interface{}
+		rules    []Rule
+		err      string
+		warnings string
+	}{
+		{"t1.1", "secret", []Rule{Required, Warn(weak)}, "", "is weak"},
+		{"t1.2", "long secret", []Rule{Required, Warn(weak)}, "", ""},
+		{"t1.3", "", []Rule{Required, Warn(weak)}, "cannot be blank", ""},
+		// the rules following a warning are applied
+		{"t2.1", "secret", []Rule{Warn(weak), Length(0, 3)}, "the length must be no more than 3", "is weak"},
+		{"t2.2", "secret", []Rule{Warn(weak), Warn(Length(0, 3)), Required}, "", "is weak; the length must be no more than 3"},
+		{"t2.3", "secret", []Rule{Warn(weak), Skip, Length(0, 3)}, "", "is weak"},
+		{"t2.4", "secret", []Rule{Warn(weak), Skip.When(true), Length(0, 3)}, "", "is weak"},
+		// non-Error errors
+		{"t3.1", "abc", []Rule{Warn(By(func(interface{}) error { return errors.New("custom") }))}, "", "custom"},
+		// nested errors
+		{"t4.1", []string{"", "a"}, []Rule{Warn(Each(Required))}, "", "0: cannot be blank."},
+		{"t4.2", []string{"", "a"}, []Rule{Each(When(true, Warn(Required)))}, "", "0: cannot be blank."},
+		// the Validate method of the value
+		{"t5.1", String123("abc"), []Rule{Warn(By(func(interface{}) error { return errWeak }))}, "error 123", "is weak"},
+	}
+
+	for _, test := range tests {
+		// warnings do not make a value invalid
+		err := Validate(test.value, test.rules...)
+		assertError(t, test.err, err, test.tag)
+
+		var warnings error
+		err = ValidateWithContext(WithWarnings(context.Background(), &warnings), test.value, test.rules...)
+		assertError(t, test.err, err, test.tag+" with context")
+		assertError(t, test.warnings, warnings, test.tag+" warnings")
+		assert.Equal(t, test.warnings != "", IsWarning(warnings), test.tag)
+	}
+
+	// rules report warnings as errors
+	err := Warn(weak).Validate("secret")
+	assertError(t, "is weak", err, "rule")
+	assert.True(t, IsWarning(err))
+
+	// internal errors are not turned into warnings
+	internal := NewInternalError(errors.New("internal"))
+	err = Validate("abc", Warn(By(func(interface{}) error { return internal })))
+	assert.Equal(t, internal, err)
+	err = Validate("abc", Warn(weak), By(func(interface{}) error { return internal }))
+	assert.Equal(t, internal, err)
+	errs, warnings := SplitWarnings(internal)
+	assert.Equal(t, internal, errs)
+	assert.Nil(t, warnings)
+
+	// the severity survives translation
+	warnings = nil
+	ctx := WithTranslator(WithWarnings(context.Background(), &warnings), mapTranslator{"password_weak": "ist schwach"})
+	assert.NoError(t, ValidateWithContext(ctx, "abc", Warn(weak)))
+	assertError(t, "ist schwach", warnings, "translated")
+	assert.True(t, IsWarning(warnings))
+}
+
+func TestErrorSeverity(t *testing.T) {
+	assert.Equal(t, SeverityError, ErrorSeverity(nil))
+	assert.Equal(t, SeverityError, ErrorSeverity(ErrRequired))
+	assert.Equal(t, SeverityError, ErrorSeverity(errors.New("abc")))
+	assert.Equal(t, SeverityWarning, ErrorSeverity(ErrRequired.(ErrorObject).SetSeverity(SeverityWarning)))
+	assert.Equal(t, SeverityWarning, ErrorSeverity(asWarning(errors.New("abc"))))
+
+	warning := errWeak.(ErrorObject).SetSeverity(SeverityWarning)
+	assert.Equal(t, SeverityWarning, warning.(ErrorObject).Severity())
+	assert.True(t, errors.Is(warning, errWeak))
+	assert.False(t, IsWarning(nil))
+	assert.False(t, IsWarning(Errors{}))
+	assert.True(t, IsWarning(Errors{"a": warning, "b": nil}))
+	assert.False(t, IsWarning(Errors{"a": warning, "b": ErrRequired}))
+	assert.True(t, IsWarning(ErrorList{warning, Errors{"c": warning}}))
+}
+
+type warnedAccount struct {
+	Name     string `json:"name"`
+	Password string `json:"password"`
+}
+
+func (a warnedAccount) ValidateWithContext(ctx context.Context) error {
+	return ValidateStructWithContext(ctx, &a,
+		Field(&a.Name, Required),
+		Field(&a.Password, Warn(Length(8, 0))),
+	)
+}
+
+func TestValidateStruct_Warnings(t *testing.T) {
+	warn := Warn(By(func(interface{}) error { return errWeak }))
+	m := Model1{A: "abc", B: "xyz"}
+
+	// warnings do not make a struct invalid
+	assert.NoError(t, ValidateStruct(&m, Field(&m.A, Required, warn)))
+	assert.NoError(t, MustCompile(&m, Field(&m.A, Required, warn)).ValidateStruct(&m))
+
+	var warnings error
+	ctx := WithWarnings(context.Background(), &warnings)
+	err := ValidateStructWithContext(ctx, &m, Field(&m.A, warn), Field(&m.B, Length(5, 10)))
+	assertError(t, "B: the length must be between 5 and 10.", err, "t1")
+	assertError(t, "A: is weak.", warnings, "t1 warnings")
+
+	// warnings do not stop the validation
+	warnings = nil
+	err = ValidateStructWithContext(WithStopOnFirstError(ctx), &m,
+		Field(&m.A, warn),
+		Field(&m.B, Length(5, 10)),
+		Field(&m.G, Required),
+	)
+	assertError(t, "B: the length must be between 5 and 10.", err, "t2")
+	assertError(t, "A: is weak.", warnings, "t2 warnings")
+
+	// the warnings of struct rules
+	warnings = nil
+	assert.NoError(t, ValidateStructWithContext(ctx, &m, StructRule(warn)))
+	assertError(t, StructErrorKey+": is weak.", warnings, "t3")
+
+	// the warnings of elements
+	warnings = nil
+	err = ValidateWithContext(WithStopOnFirstError(ctx), []string{"a", "", ""}, Each(Warn(Length(2, 5)), Required))
+	assertError(t, "1: cannot be blank.", err, "t4")
+	assertError(t, "0: the length must be between 2 and 5.", warnings, "t4 warnings")
+	warnings = nil
+	err = ValidateWithContext(WithStopOnFirstError(ctx), []string{"a", "", ""}, EachParallel(2, Warn(Length(2, 5)), Required))
+	assertError(t, "1: cannot be blank.", err, "t4 parallel")
+	assertError(t, "0: the length must be between 2 and 5.", warnings, "t4 parallel warnings")
+
+	// the warnings of nested validations are reported with their path
+	warnings = nil
+	accounts := map[string]warnedAccount{"a": {Name: "john", Password: "secret"}, "b": {Password: "long secret"}}
+	err = ValidateWithContext(ctx, accounts)
+	assertError(t, "b: (name: cannot be blank.).", err, "t5")
+	assertError(t, "a: (password: the length must be no less than 8.).", warnings, "t5 warnings")
+
+	// the warnings of successive validations are accumulated
+	err = ValidateWithContext(ctx, "abc", Warn(Length(5, 0)))
+	assert.NoError(t, err)
+	assert.Len(t, warnings, 2)
+	assertError(t, "a: (password: the length must be no less than 8.).; the length must be no less than 5", warnings, "t6")
+
+	// the warnings of batched validations are stored once
+	warnings = nil
+	err = ValidateBatched(ctx, func(ctx context.Context) error {
+		return ValidateWithContext(ctx, "abc", Warn(Length(5, 0)))
+	})
+	assert.NoError(t, err)
+	assertError(t, "the length must be no less than 5", warnings, "batched")
+}
+
+func TestErrors_FilterSeverity(t *testing.T) {
+	warning := errWeak.(ErrorObject).SetSeverity(SeverityWarning)
+	errs := Errors{
+		"a": warning,
+		"b": ErrRequired,
+		"c": nil,
+		"d": Errors{"x": warning, "y": ErrNil},
+		"e": ErrorList{warning},
+	}
+	nested := errs["d"]
+
+	assert.Equal(t, Errors{"b": ErrRequired, "d": Errors{"y": ErrNil}}, errs.FilterSeverity(SeverityError))
+	// nested errors are copied
+	assert.Len(t, nested, 2)
+
+	errs = Errors{"a": warning, "b": ErrRequired, "c": nil, "e": ErrorList{warning, ErrNil}}
+	assert.Equal(t, Errors{"a": warning, "e": warning}, errs.FilterSeverity(SeverityWarning))
+
+	errs = Errors{"a": warning, "c": nil}
+	assert.Nil(t, errs.FilterSeverity(SeverityError))
+
+	errs = Errors{"a": warning, "b": ErrRequired, "c": nil}
+	assert.Equal(t, Errors{"a": warning, "b": ErrRequired}, errs.Filter())
+}
+
+func TestWarnings_JSON(t *testing.T) {
+	err := All(Warn(Length(8, 0)), Warn(By(func(interface{}) error { return errors.New("custom") }))).Validate("secret")
+	errs := Errors{"password": err, "name": ErrRequired}
+
+	data, e := MarshalErrorJSON(errs, JSONStructured)
+	assert.NoError(t, e)
+	assert.JSONEq(t, `{
+		"name":{"code":"validation_required","message":"cannot be blank"},
+		"password":[
+			{"code":"validation_length_too_short","message":"the length must be no less than 8","params":{"min":8,"max":0},"severity":"warning"},
+			{"message":"custom","severity":"warning"}
+		]
+	}`, string(data))
+
+	// the default encoding omits the warnings
+	data, e = MarshalErrorJSON(errs, JSONMessages)
+	assert.NoError(t, e)
+	assert.JSONEq(t, `{"name":"cannot be blank"}`, string(data))
+	data, e = MarshalErrorJSON(Errors{"a": Errors{"b": err}, "c": ErrorList{err, ErrNil}}, JSONMessages)
+	assert.NoError(t, e)
+	assert.JSONEq(t, `{"c":["must be blank"]}`, string(data))
+
+	data, e = MarshalErrorJSON(errs, JSONStructured)
+	assert.NoError(t, e)
+	var decoded Errors
+	assert.NoError(t, JSONDecoder{Format: JSONStructured}.Unmarshal(data, &decoded))
+	assert.True(t, IsWarning(decoded["password"]))
+	assert.False(t, IsWarning(decoded["name"]))
+	assert.Equal(t, Errors{"name": decoded["name"]}, decoded.FilterSeverity(SeverityError))
+
+	// an unknown severity is not decoded
+	assert.Error(t, JSONDecoder{Format: JSONStructured}.Unmarshal([]byte(`{"a":{"message":"x","severity":"info"}}`), &decoded))
+
+	assert.Equal(t, []FieldError{
+		{Path: "name", Code: "validation_required", Message: "cannot be blank"},
+		{Path: "password", Code: "validation_length_too_short", Message: "the length must be no less than 8", Params: map[string]interface{}{"min": 8, "max": 0}, Severity: SeverityWarning},
+		{Path: "password", Message: "custom", Severity: SeverityWarning},
+	}, Flatten(errs, PathDotted))
+}
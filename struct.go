@@ -17,6 +17,8 @@ var (
 	ErrStructPointer = errors.New("only a pointer to a struct can be validated")
 	// GetErrorFieldName is used to get the desired field name for a struct field, overriding the default getErrorFieldName
 	GetErrorFieldName func(f *reflect.StructField) string = getErrorFieldName
+	// StructErrorKey is the key under which ValidateStruct reports the errors of the rules specified by StructRule.
+	StructErrorKey = "_struct"
 )
 
 type (
@@ -31,6 +33,10 @@ type (
 		fieldPtr         interface{}
 		rules            []Rule
 		validatePtrValue bool
+		// structKey is the error key of the rules that validate the struct as a whole, if any.
+		structKey *string
+		// fields are the rules of the nested struct specified by FieldStruct, if any.
+		fields []*FieldRules
 	}
 )
 
@@ -70,7 +76,18 @@ func ValidateStruct(structPtr interface{}, fields ...*FieldRules) error {
 // The only difference between ValidateStructWithContext and ValidateStruct is that the former will
 // validate struct fields with the provided context.
 // Please refer to ValidateStruct for the detailed instructions on how to use this function.
+// Use WithStopOnFirstError to return as soon as the first field error is found.
+// If ctx is canceled or its deadline is exceeded, the validation stops and an InternalError wrapping ctx.Err() is returned.
+// The warnings found are not returned, but stored in the sink set by WithWarnings, if any.
 func ValidateStructWithContext(ctx context.Context, structPtr interface{}, fields ...*FieldRules) error {
+	return reportWarnings(ctx, func(ctx context.Context) error {
+		return validateStruct(ctx, structPtr, fields)
+	})
+}
+
+// validateStruct validates a struct as described in ValidateStructWithContext, and returns the warnings found
+// along with the errors.
+func validateStruct(ctx context.Context, structPtr interface{}, fields []*FieldRules) error {
 	value := reflect.ValueOf(structPtr)
 	if value.Kind() != reflect.Ptr || !value.IsNil() && value.Elem().Kind() != reflect.Struct {
 		// must be a pointer to a struct
@@ -85,6 +102,26 @@ func ValidateStructWithContext(ctx context.Context, structPtr interface{}, field
 	errs := Errors{}
 
 	for i, fr := range fields {
+		if err := contextError(ctx); err != nil {
+			return err
+		}
+
+		rules, ok := bindStructRules(value, fr.rules)
+		if !ok {
+			return NewInternalError(ErrFieldRefNotFound(i))
+		}
+
+		if fr.structKey != nil {
+			failed, err := validateStructRules(ctx, errs, structPtr, rules, *fr.structKey)
+			if err != nil {
+				return err
+			}
+			if failed && stopOnFirstError(ctx) {
+				break
+			}
+			continue
+		}
+
 		fv := reflect.ValueOf(fr.fieldPtr)
 		if fv.Kind() != reflect.Ptr {
 			return NewInternalError(ErrFieldPointer(i))
@@ -101,27 +138,13 @@ func ValidateStructWithContext(ctx context.Context, structPtr interface{}, field
 			validateValue = fv.Interface()
 		}
 
-		var err error
-		if ctx == nil {
-			err = Validate(validateValue, fr.rules...)
-		} else {
-			err = ValidateWithContext(ctx, validateValue, fr.rules...)
-		}
-
+		err := validateNested(ctx, validateValue, rules...)
+		failed, err := addFieldError(errs, err, GetErrorFieldName(ft), ft.Anonymous)
 		if err != nil {
-			if ie, ok := err.(InternalError); ok && ie.InternalError() != nil {
-				return err
-			}
-			if ft.Anonymous {
-				// merge errors from anonymous struct field
-				if es, ok := err.(Errors); ok {
-					for name, value := range es {
-						errs[name] = value
-					}
-					continue
-				}
-			}
-			errs[GetErrorFieldName(ft)] = err
+			return err
+		}
+		if failed && stopOnFirstError(ctx) {
+			break
 		}
 	}
 
@@ -131,6 +154,52 @@ func ValidateStructWithContext(ctx context.Context, structPtr interface{}, field
 	return nil
 }
 
+// validateStructRules validates the struct with the rules of a StructRule or Group and records their errors
+// into errs under the given key. It returns whether the rules failed with errors other than warnings,
+// and the internal error, if any.
+func validateStructRules(ctx context.Context, errs Errors, structPtr interface{}, rules []Rule, key string) (bool, error) {
+	err := translateWithContext(ctx, validateRules(ctx, structPtr, rules))
+	if err == nil {
+		return false, nil
+	}
+	if ie, ok := err.(InternalError); ok && ie.InternalError() != nil {
+		return true, err
+	}
+	if es, ok := err.(Errors); ok {
+		// merge errors keyed by field names, the empty key standing for the struct itself
+		for name, value := range es {
+			if name == "" {
+				name = key
+			}
+			errs[name] = value
+		}
+	} else {
+		errs[key] = err
+	}
+	return !IsWarning(err), nil
+}
+
+// addFieldError records the validation error of a struct field into errs under the given name.
+// The errors of an anonymous struct field are merged into errs. It returns whether err is not nil and not
+// a warning, and err itself if it is an internal error.
+func addFieldError(errs Errors, err error, name string, anonymous bool) (bool, error) {
+	if err == nil {
+		return false, nil
+	}
+	if ie, ok := err.(InternalError); ok && ie.InternalError() != nil {
+		return true, err
+	}
+	if es, ok := err.(Errors); ok && anonymous {
+		// merge errors from anonymous struct field
+		for name, value := range es {
+			errs[name] = value
+		}
+	} else {
+		errs[name] = err
+	}
+	return !IsWarning(err), nil
+}
+
 // Field specifies a struct field and the corresponding validation rules.
 // The struct field must be specified as a pointer to it.
 func Field(fieldPtr interface{}, rules ...Rule) *FieldRules {
@@ -140,6 +209,34 @@ func Field(fieldPtr interface{}, rules ...Rule) *FieldRules {
 	}
 }
 
+// StructRule specifies rules that validate the struct as a whole, such as invariants involving several fields.
+// The rules are given the pointer to the struct being validated, and their errors are reported under StructErrorKey,
+// unless they are Errors, which are merged into the errors of the struct (an error keyed by an empty string
+// is then reported under StructErrorKey). For example,
+//
+//	err := validation.ValidateStruct(&c,
+//	    validation.Field(&c.Name, validation.Required),
+//	    validation.StructRule(validation.By(func(interface{}) error {
+//	        if c.Email == "" && c.Phone == "" {
+//	            return errors.New("either email or phone is required")
+//	        }
+//	        return nil
+//	    })),
+//	)
+//
+// Only the given rules are evaluated: the Validate method of the struct is not called again.
+func StructRule(rules ...Rule) *FieldRules {
+	return Group(StructErrorKey, rules...)
+}
+
+// Group is like StructRule, but reports the errors of the rules under the given key.
+func Group(key string, rules ...Rule) *FieldRules {
+	return &FieldRules{
+		rules:     rules,
+		structKey: &key,
+	}
+}
+
 // FieldStruct specifies a struct field and the corresponding validation field rules.
 // The struct field must be specified as a pointer to struct.
 // example,
@@ -161,13 +258,14 @@ func FieldStruct(structPtr interface{}, fields ...*FieldRules) *FieldRules {
 		fieldPtr: structPtr,
 		rules: []Rule{&inlineRule{
 			f: func(value interface{}) error {
-				return ValidateStruct(value, fields...)
+				return validateStruct(nil, value, fields)
 			},
 			fc: func(ctx context.Context, value interface{}) error {
-				return ValidateStructWithContext(ctx, value, fields...)
+				return validateStruct(ctx, value, fields)
 			},
 		}},
 		validatePtrValue: true,
+		fields:           fields,
 	}
 }
 
@@ -6,6 +6,7 @@ package validation
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"strings"
@@ -350,3 +351,74 @@ func TestErrorFieldName(t *testing.T) {
 		})
 	}
 }
+
+type contact struct {
+	Name  string
+	Email string
+	Phone string
+	Items []int
+	Total int
+}
+
+func (c contact) Validate() error {
+	return ValidateStruct(&c,
+		Field(&c.Name, Required),
+		StructRule(By(func(value interface{}) error {
+			c := value.(*contact)
+			if c.Email == "" && c.Phone == "" {
+				return errors.New("either email or phone is required")
+			}
+			return nil
+		})),
+		Group("Total", By(func(interface{}) error {
+			sum := 0
+			for _, item := range c.Items {
+				sum += item
+			}
+			if sum != c.Total {
+				return errors.New("must be the sum of the items")
+			}
+			return nil
+		})),
+	)
+}
+
+func TestStructRule(t *testing.T) {
+	assert.Nil(t, contact{Name: "a", Email: "a@example.com"}.Validate())
+	assertError(t, "_struct: either email or phone is required.", contact{Name: "a"}.Validate(), "t1")
+	assertError(t, "Name: cannot be blank; Total: must be the sum of the items; _struct: either email or phone is required.", contact{Items: []int{1}}.Validate(), "t2")
+
+	// the struct is validated by the rules only, and may be Validatable itself
+	c := contact{Phone: "1"}
+	err := Validate(&c)
+	assertError(t, "Name: cannot be blank.", err, "t3")
+
+	// errors keyed by field names are merged
+	err = ValidateStruct(&c, StructRule(By(func(interface{}) error {
+		return Errors{"Email": ErrRequired}
+	})), Field(&c.Name, Required))
+	assertError(t, "Email: cannot be blank; Name: cannot be blank.", err, "t4")
+
+	// internal errors
+	err = ValidateStruct(&c, Field(&c.Name, Required), StructRule(By(func(interface{}) error {
+		return NewInternalError(errors.New("internal"))
+	})))
+	assertError(t, "internal", err, "t5")
+
+	// Skip
+	err = ValidateStruct(&c, StructRule(Skip, By(func(interface{}) error { return errors.New("x") })))
+	assert.Nil(t, err)
+
+	// context
+	ctx := WithStopOnFirstError(WithTranslator(context.Background(), mapTranslator{"validation_required": "darf nicht leer sein"}))
+	err = ValidateStructWithContext(ctx, &c, StructRule(By(func(interface{}) error { return ErrRequired })), Field(&c.Name, Required))
+	assertError(t, "_struct: darf nicht leer sein.", err, "t6")
+
+	// custom key
+	orig := StructErrorKey
+	defer func() {
+		StructErrorKey = orig
+	}()
+	StructErrorKey = ""
+	assertError(t, ": either email or phone is required.", contact{Name: "a"}.Validate(), "t7")
+}
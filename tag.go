@@ -0,0 +1,428 @@
+// Copyright 2016 Qiang Xue, 2022 Jellydator. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ValidationTag is the struct tag name used by ValidateTagged to look up the validation rules of a struct field.
+var ValidationTag = "validate"
+
+// TagRuleFunc creates a validation rule from a token of a validation tag.
+// The param is the text following "=" in the token (empty if there is none),
+// and typ is the type of the struct field the rule is going to validate.
+type TagRuleFunc func(param string, typ reflect.Type) (Rule, error)
+
+var (
+	tagRulesMu sync.RWMutex
+	tagRules   = map[string]TagRuleFunc{
+		"required":         staticTagRule(Required),
+		"nil_or_not_empty": staticTagRule(NilOrNotEmpty),
+		"not_nil":          staticTagRule(NotNil),
+		"nil":              staticTagRule(Nil),
+		"empty":            staticTagRule(Empty),
+		"length":           lengthTagRule(Length),
+		"rune_length":      lengthTagRule(RuneLength),
+		"min":              thresholdTagRule(Min),
+		"max":              thresholdTagRule(Max),
+		"multiple_of":      multipleOfTagRule,
+		"match":            matchTagRule,
+		"in":               inTagRule,
+		"not_in":           notInTagRule,
+		"date":             dateTagRule,
+	}
+
+	// tagPlans caches the rules parsed from the validation tags of a struct type, keyed by tagPlanKey.
+	tagPlans sync.Map
+	// tagPlansGen is incremented, with tagRulesMu held, every time tagPlans is cleared.
+	tagPlansGen uint64
+)
+
+// tagPlanKey identifies the rules parsed from a struct type with a given validation tag name.
+type tagPlanKey struct {
+	tag string
+	typ reflect.Type
+}
+
+// RegisterTagRule registers a named rule that can be referenced in validation tags.
+// A rule registered under an existing name replaces the previous one, including the built-in rules.
+// For example, the following registers a rule that can be used as `validate:"prefix=abc"`:
+//
+//	validation.RegisterTagRule("prefix", func(param string, _ reflect.Type) (validation.Rule, error) {
+//	    return validation.By(func(value interface{}) error {
+//	        s, _ := value.(string)
+//	        if !strings.HasPrefix(s, param) {
+//	            return errors.New("must start with " + param)
+//	        }
+//	        return nil
+//	    }), nil
+//	})
+func RegisterTagRule(name string, fn TagRuleFunc) {
+	tagRulesMu.Lock()
+	defer tagRulesMu.Unlock()
+
+	tagRules[name] = fn
+	// rules that were already parsed may refer to the replaced rule
+	tagPlansGen++
+	tagPlans.Range(func(key, _ interface{}) bool {
+		tagPlans.Delete(key)
+		return true
+	})
+}
+
+// ValidateTagged validates a struct by checking its fields against the rules declared in their validation tags.
+// It is an alternative to ValidateStruct for structs whose rules can be expressed declaratively.
+// Note that the struct being validated must be specified as a pointer to it. If the pointer is nil, it is considered valid.
+// The rules of a field are listed as comma-separated tokens in the tag named by ValidationTag. For example,
+//
+//	type User struct {
+//	    Name  string `json:"name" validate:"required,length=1:64"`
+//	    Email string `json:"email" validate:"required,is=email"`
+//	    Role  string `json:"role" validate:"in=admin|user"`
+//	}
+//	err := validation.ValidateTagged(&user)
+//
+// The following tokens are supported out of the box:
+//
+//	required, nil_or_not_empty, not_nil, nil, empty  the corresponding rules
+//	length=min:max, rune_length=min:max               Length and RuneLength, omitted bounds are 0
+//	min=v, max=v, multiple_of=v                       Min, Max and MultipleOf, v is parsed as the field type
+//	match=regexp                                      Match
+//	in=a|b|c, not_in=a|b|c                            In and NotIn, values are parsed as the field type
+//	date=layout                                       Date
+//
+// The is package registers the is=name token (e.g. is=email or is=uuid_v4) for its rules when imported.
+// Additional tokens can be registered with RegisterTagRule. A comma inside a token must be escaped as "\,".
+//
+// Struct fields without a validation tag are validated like ValidateStruct would do: values implementing Validatable
+// are validated. Nested structs that are not Validatable but declare validation tags are validated recursively.
+// Fields tagged with "-" and unexported fields are ignored.
+//
+// The errors are reported in the same shape as ValidateStruct, using GetErrorFieldName for the field names.
+func ValidateTagged(structPtr interface{}) error {
+	return ValidateTaggedWithContext(nil, structPtr)
+}
+
+// ValidateTaggedWithContext validates a struct with the given context by checking its fields against
+// the rules declared in their validation tags.
+// Please refer to ValidateTagged for the detailed instructions on how to use this function.
+func ValidateTaggedWithContext(ctx context.Context, structPtr interface{}) error {
+	value := reflect.ValueOf(structPtr)
+	if value.Kind() != reflect.Ptr || !value.IsNil() && value.Elem().Kind() != reflect.Struct {
+		// must be a pointer to a struct
+		return NewInternalError(ErrStructPointer)
+	}
+	if value.IsNil() {
+		// treat a nil struct pointer as valid
+		return nil
+	}
+	value = value.Elem()
+
+	plan, err := tagPlanOf(value.Type())
+	if err != nil {
+		return NewInternalError(err)
+	}
+
+	fields := make([]*FieldRules, len(plan))
+	for i, fp := range plan {
+		fields[i] = Field(value.Field(fp.index).Addr().Interface(), fp.rules...)
+	}
+
+	return ValidateStructWithContext(ctx, structPtr, fields...)
+}
+
+// tagField holds the rules parsed from the validation tag of a struct field.
+type tagField struct {
+	index int
+	rules []Rule
+}
+
+// tagPlanOf returns the cached rules of the given struct type, parsing its validation tags if needed.
+func tagPlanOf(t reflect.Type) ([]tagField, error) {
+	key := tagPlanKey{tag: ValidationTag, typ: t}
+	if plan, ok := tagPlans.Load(key); ok {
+		return plan.([]tagField), nil
+	}
+
+	tagRulesMu.RLock()
+	gen := tagPlansGen
+	tagRulesMu.RUnlock()
+
+	var plan []tagField
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		tag, ok := sf.Tag.Lookup(key.tag)
+		if tag == "-" {
+			continue
+		}
+
+		var rules []Rule
+		if ok {
+			for _, token := range splitTag(tag) {
+				rule, err := parseTagToken(token, sf.Type)
+				if err != nil {
+					return nil, fmt.Errorf("field %v: %w", sf.Name, err)
+				}
+				rules = append(rules, rule)
+			}
+		}
+		if hasTaggedFields(sf.Type, key.tag) {
+			rules = append(rules, nestedTagRule{})
+		}
+		// fields without rules are still listed so that Validatable values get validated
+		plan = append(plan, tagField{index: i, rules: rules})
+	}
+
+	// a plan built while a rule was being registered may refer to the replaced rule and is not cached
+	tagRulesMu.RLock()
+	if gen == tagPlansGen {
+		tagPlans.Store(key, plan)
+	}
+	tagRulesMu.RUnlock()
+	return plan, nil
+}
+
+// hasTaggedFields checks if the given type is a struct (or a pointer to it) that is not validatable
+// and has at least one field with the given validation tag, either directly or in a nested struct.
+func hasTaggedFields(t reflect.Type, tagName string) bool {
+	return hasTaggedFieldsVisited(t, tagName, map[reflect.Type]bool{})
+}
+
+func hasTaggedFieldsVisited(t reflect.Type, tagName string, visited map[reflect.Type]bool) bool {
+	if t.Implements(validatableType) || t.Implements(validatableWithContextType) {
+		// the value validates itself
+		return false
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || visited[t] {
+		return false
+	}
+	visited[t] = true
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		if tag, ok := sf.Tag.Lookup(tagName); ok && tag != "-" {
+			return true
+		}
+		if hasTaggedFieldsVisited(sf.Type, tagName, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// nestedTagRule validates a nested struct with ValidateTagged.
+type nestedTagRule struct{}
+
+// Validate checks if the given value is valid or not.
+func (r nestedTagRule) Validate(value interface{}) error {
+	return r.ValidateWithContext(nil, value)
+}
+
+// ValidateWithContext checks if the given value is valid or not.
+func (r nestedTagRule) ValidateWithContext(ctx context.Context, value interface{}) error {
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return nil
+		}
+	case reflect.Struct:
+		// the field value is a copy, validate it through a pointer
+		ptr := reflect.New(rv.Type())
+		ptr.Elem().Set(rv)
+		rv = ptr
+	default:
+		return nil
+	}
+	return ValidateTaggedWithContext(ctx, rv.Interface())
+}
+
+// splitTag splits a validation tag into its tokens. A comma can be escaped as "\,".
+func splitTag(tag string) []string {
+	var (
+		tokens []string
+		token  strings.Builder
+	)
+	for i := 0; i < len(tag); i++ {
+		switch {
+		case tag[i] == '\\' && i+1 < len(tag) && tag[i+1] == ',':
+			token.WriteByte(',')
+			i++
+		case tag[i] == ',':
+			tokens = append(tokens, token.String())
+			token.Reset()
+		default:
+			token.WriteByte(tag[i])
+		}
+	}
+	tokens = append(tokens, token.String())
+
+	res := tokens[:0]
+	for _, t := range tokens {
+		if t = strings.TrimSpace(t); t != "" {
+			res = append(res, t)
+		}
+	}
+	return res
+}
+
+// parseTagToken creates the rule described by a single validation tag token.
+func parseTagToken(token string, typ reflect.Type) (Rule, error) {
+	name, param, _ := strings.Cut(token, "=")
+
+	tagRulesMu.RLock()
+	fn, ok := tagRules[name]
+	tagRulesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown validation rule %q", name)
+	}
+
+	rule, err := fn(param, typ)
+	if err != nil {
+		return nil, fmt.Errorf("invalid validation rule %q: %w", token, err)
+	}
+	return rule, nil
+}
+
+// parseTagValue parses a validation tag parameter as a value of the given type (or its element type for pointers).
+func parseTagValue(param string, typ reflect.Type) (interface{}, error) {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	var v interface{}
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(param, 10, typ.Bits())
+		if err != nil {
+			return nil, err
+		}
+		v = n
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(param, 10, typ.Bits())
+		if err != nil {
+			return nil, err
+		}
+		v = n
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(param, typ.Bits())
+		if err != nil {
+			return nil, err
+		}
+		v = n
+	case reflect.Bool:
+		b, err := strconv.ParseBool(param)
+		if err != nil {
+			return nil, err
+		}
+		v = b
+	case reflect.String:
+		v = param
+	default:
+		return nil, fmt.Errorf("type not supported: %v", typ)
+	}
+
+	return reflect.ValueOf(v).Convert(typ).Interface(), nil
+}
+
+func staticTagRule(rule Rule) TagRuleFunc {
+	return func(string, reflect.Type) (Rule, error) {
+		return rule, nil
+	}
+}
+
+func lengthTagRule(fn func(min, max int) LengthRule) TagRuleFunc {
+	return func(param string, _ reflect.Type) (Rule, error) {
+		minStr, maxStr, _ := strings.Cut(param, ":")
+		var min, max int
+		var err error
+		if minStr != "" {
+			if min, err = strconv.Atoi(minStr); err != nil {
+				return nil, err
+			}
+		}
+		if maxStr != "" {
+			if max, err = strconv.Atoi(maxStr); err != nil {
+				return nil, err
+			}
+		}
+		return fn(min, max), nil
+	}
+}
+
+func thresholdTagRule(fn func(threshold interface{}) ThresholdRule) TagRuleFunc {
+	return func(param string, typ reflect.Type) (Rule, error) {
+		v, err := parseTagValue(param, typ)
+		if err != nil {
+			return nil, err
+		}
+		return fn(v), nil
+	}
+}
+
+func multipleOfTagRule(param string, typ reflect.Type) (Rule, error) {
+	v, err := parseTagValue(param, typ)
+	if err != nil {
+		return nil, err
+	}
+	return MultipleOf(v), nil
+}
+
+func matchTagRule(param string, _ reflect.Type) (Rule, error) {
+	re, err := regexp.Compile(param)
+	if err != nil {
+		return nil, err
+	}
+	return Match(re), nil
+}
+
+func inTagRule(param string, typ reflect.Type) (Rule, error) {
+	values, err := parseTagValues(param, typ)
+	if err != nil {
+		return nil, err
+	}
+	return In(values...), nil
+}
+
+func notInTagRule(param string, typ reflect.Type) (Rule, error) {
+	values, err := parseTagValues(param, typ)
+	if err != nil {
+		return nil, err
+	}
+	return NotIn(values...), nil
+}
+
+func dateTagRule(param string, _ reflect.Type) (Rule, error) {
+	return Date(param), nil
+}
+
+// parseTagValues parses a "|" separated list of values as values of the given type.
+func parseTagValues(param string, typ reflect.Type) ([]interface{}, error) {
+	parts := strings.Split(param, "|")
+	values := make([]interface{}, len(parts))
+	for i, p := range parts {
+		v, err := parseTagValue(p, typ)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+	return values, nil
+}
@@ -0,0 +1,212 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type TaggedAddress struct {
+	Street string `json:"street" validate:"required,length=1:10"`
+	Zip    string `json:"zip" validate:"match=^[0-9]{5}$"`
+}
+
+type TaggedEmbedded struct {
+	Code string `json:"code" validate:"required"`
+}
+
+type TaggedUser struct {
+	TaggedEmbedded
+	Name     string         `json:"name" validate:"required,length=1:5"`
+	Age      int            `json:"age" validate:"min=18,max=120"`
+	Score    *float64       `json:"score" validate:"max=1.5"`
+	Role     status         `json:"role" validate:"in=admin|user"`
+	Count    uint           `json:"count" validate:"multiple_of=2"`
+	Born     string         `json:"born" validate:"date=2006-01-02"`
+	Nick     string         `json:"nick" validate:"not_in=root|admin"`
+	Ignored  string         `json:"ignored" validate:"-"`
+	Model    String123      `json:"model"`
+	Address  TaggedAddress  `json:"address"`
+	Previous *TaggedAddress `json:"previous"`
+	hidden   string         `validate:"required"`
+}
+
+func TestValidateTagged(t *testing.T) {
+	score := 2.5
+	u := TaggedUser{
+		Name:     "abcdef",
+		Age:      10,
+		Score:    &score,
+		Role:     "guest",
+		Count:    3,
+		Born:     "2000-13-01",
+		Nick:     "root",
+		Model:    "abc",
+		Address:  TaggedAddress{Zip: "1234"},
+		Previous: &TaggedAddress{Street: "main street 1"},
+	}
+	err := ValidateTagged(&u)
+	assert.EqualError(t, err, "address: (street: cannot be blank; zip: must be in a valid format.); age: must be no less than 18; born: must be a valid date; code: cannot be blank; count: must be multiple of 2; model: error 123; name: the length must be between 1 and 5; nick: must not be in list; previous: (street: the length must be between 1 and 10.); role: must be a valid value; score: must be no greater than 1.5.")
+
+	// the errors have the same shape as ValidateStruct
+	expected := ValidateStruct(&u,
+		Field(&u.Code, Required),
+		Field(&u.Name, Required, Length(1, 5)),
+		Field(&u.Age, Min(18), Max(120)),
+	)
+	es := err.(Errors)
+	for key, value := range expected.(Errors) {
+		assert.Equal(t, value, es[key], key)
+	}
+
+	valid := TaggedUser{
+		TaggedEmbedded: TaggedEmbedded{Code: "x"},
+		Name:           "abc",
+		Age:            20,
+		Role:           "user",
+		Count:          4,
+		Born:           "2000-01-01",
+		Model:          "123",
+		Address:        TaggedAddress{Street: "main"},
+	}
+	assert.NoError(t, ValidateTagged(&valid))
+
+	var nilUser *TaggedUser
+	assert.NoError(t, ValidateTagged(nilUser))
+	assert.Equal(t, NewInternalError(ErrStructPointer), ValidateTagged(valid))
+}
+
+func TestValidateTaggedWithContext(t *testing.T) {
+	m := struct {
+		A Model4 `json:"a"`
+		B string `json:"b" validate:"required"`
+	}{A: Model4{A: "xyz"}}
+	err := ValidateTaggedWithContext(context.Background(), &m)
+	assert.EqualError(t, err, "a: (A: error abc.); b: cannot be blank.")
+}
+
+func TestValidateTagged_InvalidTag(t *testing.T) {
+	tests := []struct {
+		tag   string
+		value interface{}
+		err   string
+	}{
+		{"t1", &struct {
+			A string `validate:"unknown"`
+		}{}, `field A: unknown validation rule "unknown"`},
+		{"t2", &struct {
+			A int `validate:"min=abc"`
+		}{}, `field A: invalid validation rule "min=abc": strconv.ParseInt: parsing "abc": invalid syntax`},
+		{"t3", &struct {
+			A string `validate:"length=a:1"`
+		}{}, `field A: invalid validation rule "length=a:1": strconv.Atoi: parsing "a": invalid syntax`},
+		{"t4", &struct {
+			A string `validate:"match=("`
+		}{}, "field A: invalid validation rule \"match=(\": error parsing regexp: missing closing ): `(`"},
+		{"t5", &struct {
+			A []int `validate:"in=1|2"`
+		}{}, `field A: invalid validation rule "in=1|2": type not supported: []int`},
+	}
+	for _, test := range tests {
+		err := ValidateTagged(test.value)
+		if assert.Error(t, err, test.tag) {
+			_, ok := err.(InternalError)
+			assert.True(t, ok, test.tag)
+			assert.EqualError(t, err, test.err, test.tag)
+		}
+	}
+}
+
+func TestRegisterTagRule(t *testing.T) {
+	RegisterTagRule("prefix", func(param string, _ reflect.Type) (Rule, error) {
+		return By(func(value interface{}) error {
+			if s, _ := value.(string); !strings.HasPrefix(s, param) {
+				return errors.New("must start with " + param)
+			}
+			return nil
+		}), nil
+	})
+
+	v := struct {
+		A string `json:"a" validate:"required,prefix=ab\\,c"`
+	}{A: "abc"}
+	assert.EqualError(t, ValidateTagged(&v), "a: must start with ab,c.")
+	v.A = "ab,cd"
+	assert.NoError(t, ValidateTagged(&v))
+}
+
+func TestRegisterTagRule_Concurrent(t *testing.T) {
+	version := func(n int) TagRuleFunc {
+		return func(string, reflect.Type) (Rule, error) {
+			return By(func(interface{}) error {
+				return errors.New("version " + strconv.Itoa(n))
+			}), nil
+		}
+	}
+	v := struct {
+		A string `json:"a" validate:"version"`
+	}{}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_ = ValidateTagged(&v)
+			}
+		}()
+	}
+	for n := 1; n <= 100; n++ {
+		RegisterTagRule("version", version(n))
+	}
+	wg.Wait()
+
+	// no plan built with a replaced rule is left in the cache
+	assert.EqualError(t, ValidateTagged(&v), "a: version 100.")
+}
+
+func TestValidationTag(t *testing.T) {
+	v := struct {
+		A string `json:"a" validate:"required" check:"length=2:3"`
+	}{A: "abcd"}
+	assert.NoError(t, ValidateTagged(&v))
+
+	defer func(tag string) {
+		ValidationTag = tag
+	}(ValidationTag)
+	ValidationTag = "check"
+	assert.EqualError(t, ValidateTagged(&v), "a: the length must be between 2 and 3.")
+}
+
+func Test_splitTag(t *testing.T) {
+	assert.Equal(t, []string{"a", "b=1", "c=x,y"}, splitTag(" a , b=1,,c=x\\,y "))
+	assert.Empty(t, splitTag(""))
+}
+
+func Test_parseTagValue(t *testing.T) {
+	v, err := parseTagValue("5", reflect.TypeOf(int8(0)))
+	assert.NoError(t, err)
+	assert.Equal(t, int8(5), v)
+
+	_, err = parseTagValue("500", reflect.TypeOf(int8(0)))
+	assert.Error(t, err)
+
+	v, err = parseTagValue("5", reflect.TypeOf(new(uint)))
+	assert.NoError(t, err)
+	assert.Equal(t, uint(5), v)
+
+	v, err = parseTagValue("true", reflect.TypeOf(false))
+	assert.NoError(t, err)
+	assert.Equal(t, true, v)
+
+	v, err = parseTagValue("abc", reflect.TypeOf(status("")))
+	assert.NoError(t, err)
+	assert.Equal(t, status("abc"), v)
+}
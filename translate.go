@@ -0,0 +1,70 @@
+// Copyright 2016 Qiang Xue, 2022 Jellydator. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import "context"
+
+// Translator translates the messages of validation errors, typically based on Error.Code().
+// The i18n sub-package provides a catalog based implementation.
+type Translator interface {
+	// Translate returns the message template that should replace the message of the given error.
+	// The template is rendered with the error's params, just like the messages of the built-in errors.
+	// The boolean result is false if no translation is available.
+	Translate(err Error) (string, bool)
+}
+
+type translatorKey struct{}
+
+// WithTranslator returns a copy of ctx that carries the given Translator.
+// ValidateWithContext, ValidateStructWithContext and the other context-aware validation functions
+// use it to translate the validation errors they return, so that each request can get its own language.
+func WithTranslator(ctx context.Context, t Translator) context.Context {
+	return context.WithValue(ctx, translatorKey{}, t)
+}
+
+// TranslatorFromContext returns the Translator carried by ctx, if any.
+func TranslatorFromContext(ctx context.Context) (Translator, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	t, ok := ctx.Value(translatorKey{}).(Translator)
+	return t, ok && t != nil
+}
+
+// Translate translates the messages of the validation errors contained in err using the given Translator.
+// Errors are walked recursively and a translated copy is returned; err itself is not modified.
+// Errors that do not implement Error, or for which no translation is found, are returned as is.
+func Translate(err error, t Translator) error {
+	switch e := err.(type) {
+	case Errors:
+		es := make(Errors, len(e))
+		for key, value := range e {
+			es[key] = Translate(value, t)
+		}
+		return es
+	case ErrorList:
+		el := make(ErrorList, len(e))
+		for i, value := range e {
+			el[i] = Translate(value, t)
+		}
+		return el
+	case Error:
+		if message, ok := t.Translate(e); ok {
+			return e.SetMessage(message)
+		}
+	}
+	return err
+}
+
+// translateWithContext translates err with the Translator carried by ctx, if any.
+func translateWithContext(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	if t, ok := TranslatorFromContext(ctx); ok {
+		return Translate(err, t)
+	}
+	return err
+}
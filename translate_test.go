@@ -0,0 +1,59 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mapTranslator map[string]string
+
+func (t mapTranslator) Translate(err Error) (string, bool) {
+	s, ok := t[err.Code()]
+	return s, ok
+}
+
+func TestTranslate(t *testing.T) {
+	tr := mapTranslator{
+		"validation_required":         "darf nicht leer sein",
+		"validation_length_too_short": "muss mindestens {{.min}} Zeichen lang sein",
+	}
+	other := errors.New("other")
+	err := Errors{
+		"a": ErrRequired,
+		"b": Errors{"0": Length(2, 0).Validate("x")},
+		"c": ErrNil,
+		"d": other,
+		"e": ErrorList{ErrRequired, ErrNil},
+	}
+	res := Translate(err, tr)
+	assert.EqualError(t, res, "a: darf nicht leer sein; b: (0: muss mindestens 2 Zeichen lang sein.); c: must be blank; d: other; e: darf nicht leer sein; must be blank.")
+	// the original errors are not modified
+	assert.EqualError(t, err, "a: cannot be blank; b: (0: the length must be no less than 2.); c: must be blank; d: other; e: cannot be blank; must be blank.")
+
+	assert.Nil(t, Translate(nil, tr))
+	assert.Equal(t, other, Translate(other, tr))
+}
+
+func TestWithTranslator(t *testing.T) {
+	tr := mapTranslator{"validation_required": "darf nicht leer sein"}
+
+	_, ok := TranslatorFromContext(nil)
+	assert.False(t, ok)
+	_, ok = TranslatorFromContext(context.Background())
+	assert.False(t, ok)
+
+	ctx := WithTranslator(context.Background(), tr)
+	got, ok := TranslatorFromContext(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, tr, got)
+
+	assert.EqualError(t, ValidateWithContext(ctx, "", Required), "darf nicht leer sein")
+	assert.EqualError(t, ValidateWithContext(ctx, []String123{"123", ""}, Each(Required)), "1: darf nicht leer sein.")
+	assert.EqualError(t, Validate("", Required), "cannot be blank")
+
+	m := map[string]interface{}{"a": ""}
+	assert.EqualError(t, ValidateWithContext(ctx, m, Map(Key("a", Required))), "a: darf nicht leer sein.")
+}
@@ -0,0 +1,252 @@
+// Copyright 2016 Qiang Xue, 2022 Jellydator. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"context"
+	"time"
+)
+
+type (
+	// TypedRule represents a validation rule for values of type T.
+	// A TypedRule is also a Rule, so it can be used anywhere a Rule is accepted.
+	TypedRule[T any] interface {
+		Rule
+		// ValidateTyped validates a value of type T and returns an error if validation fails.
+		ValidateTyped(value T) error
+	}
+
+	// Ordered is a constraint that permits the types supported by Min and Max.
+	Ordered interface {
+		~int | ~int8 | ~int16 | ~int32 | ~int64 |
+			~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+			~float32 | ~float64 |
+			time.Time
+	}
+
+	// Text is a constraint that permits the types supported by Length and RuneLength.
+	Text interface {
+		~string | ~[]byte
+	}
+)
+
+// Typed wraps a Rule into a TypedRule for values of type T.
+// Use it to mix untyped rules such as Required or the rules in the is package with typed rules.
+func Typed[T any](rule Rule) TypedRule[T] {
+	return typedRule[T]{rule: rule}
+}
+
+// ValidateValue validates the given value of type T with the given typed rules and returns the validation error, if any.
+// It behaves exactly like Validate, except that the compiler checks that every rule can handle values of type T.
+func ValidateValue[T any](value T, rules ...TypedRule[T]) error {
+	return Validate(value, untypedRules(rules)...)
+}
+
+// ValidateValueWithContext validates the given value of type T with the given context and typed rules.
+// It behaves exactly like ValidateWithContext, except that the compiler checks that every rule can handle values of type T.
+func ValidateValueWithContext[T any](ctx context.Context, value T, rules ...TypedRule[T]) error {
+	return ValidateWithContext(ctx, value, untypedRules(rules)...)
+}
+
+// FieldOf specifies a struct field of type T and the corresponding typed validation rules.
+// The struct field must be specified as a pointer to it.
+func FieldOf[T any](fieldPtr *T, rules ...TypedRule[T]) *FieldRules {
+	return Field(fieldPtr, untypedRules(rules)...)
+}
+
+// untypedRules converts typed rules back into plain rules. Rules wrapped by Typed are unwrapped
+// so that special rules such as Skip keep their meaning.
+func untypedRules[T any](rules []TypedRule[T]) []Rule {
+	rs := make([]Rule, len(rules))
+	for i, rule := range rules {
+		if tr, ok := rule.(typedRule[T]); ok {
+			rs[i] = tr.rule
+		} else {
+			rs[i] = rule
+		}
+	}
+	return rs
+}
+
+type typedRule[T any] struct {
+	rule Rule
+}
+
+// Validate checks if the given value is valid or not.
+func (r typedRule[T]) Validate(value interface{}) error {
+	return r.rule.Validate(value)
+}
+
+// ValidateWithContext checks if the given value is valid or not.
+func (r typedRule[T]) ValidateWithContext(ctx context.Context, value interface{}) error {
+	if rc, ok := r.rule.(RuleWithContext); ok {
+		return rc.ValidateWithContext(ctx, value)
+	}
+	return r.rule.Validate(value)
+}
+
+// ValidateTyped checks if the given value is valid or not.
+func (r typedRule[T]) ValidateTyped(value T) error {
+	return r.rule.Validate(value)
+}
+
+// ThresholdRuleOf is a typed version of ThresholdRule.
+type ThresholdRuleOf[T Ordered] struct {
+	rule ThresholdRule
+}
+
+// MinOf returns a typed validation rule that checks if a value is greater or equal than the specified value.
+// Please refer to Min for more details.
+func MinOf[T Ordered](min T) ThresholdRuleOf[T] {
+	return ThresholdRuleOf[T]{rule: Min(min)}
+}
+
+// MaxOf returns a typed validation rule that checks if a value is less or equal than the specified value.
+// Please refer to Max for more details.
+func MaxOf[T Ordered](max T) ThresholdRuleOf[T] {
+	return ThresholdRuleOf[T]{rule: Max(max)}
+}
+
+// Exclusive sets the comparison to exclude the boundary value.
+func (r ThresholdRuleOf[T]) Exclusive() ThresholdRuleOf[T] {
+	r.rule = r.rule.Exclusive()
+	return r
+}
+
+// Error sets the error message for the rule.
+func (r ThresholdRuleOf[T]) Error(message string) ThresholdRuleOf[T] {
+	r.rule = r.rule.Error(message)
+	return r
+}
+
+// ErrorObject sets the error struct for the rule.
+func (r ThresholdRuleOf[T]) ErrorObject(err Error) ThresholdRuleOf[T] {
+	r.rule = r.rule.ErrorObject(err)
+	return r
+}
+
+// Validate checks if the given value is valid or not.
+func (r ThresholdRuleOf[T]) Validate(value interface{}) error {
+	return r.rule.Validate(value)
+}
+
+// ValidateTyped checks if the given value is valid or not.
+func (r ThresholdRuleOf[T]) ValidateTyped(value T) error {
+	return r.rule.Validate(value)
+}
+
+// LengthRuleOf is a typed version of LengthRule.
+type LengthRuleOf[T Text] struct {
+	rule LengthRule
+}
+
+// LengthOf returns a typed validation rule that checks if a value's length is within the specified range.
+// Please refer to Length for more details.
+func LengthOf[T Text](min, max int) LengthRuleOf[T] {
+	return LengthRuleOf[T]{rule: Length(min, max)}
+}
+
+// RuneLengthOf returns a typed validation rule that checks if a string's rune length is within the specified range.
+// Please refer to RuneLength for more details.
+func RuneLengthOf[T Text](min, max int) LengthRuleOf[T] {
+	return LengthRuleOf[T]{rule: RuneLength(min, max)}
+}
+
+// Error sets the error message for the rule.
+func (r LengthRuleOf[T]) Error(message string) LengthRuleOf[T] {
+	r.rule = r.rule.Error(message)
+	return r
+}
+
+// ErrorObject sets the error struct for the rule.
+func (r LengthRuleOf[T]) ErrorObject(err Error) LengthRuleOf[T] {
+	r.rule = r.rule.ErrorObject(err)
+	return r
+}
+
+// Validate checks if the given value is valid or not.
+func (r LengthRuleOf[T]) Validate(value interface{}) error {
+	return r.rule.Validate(value)
+}
+
+// ValidateTyped checks if the given value is valid or not.
+func (r LengthRuleOf[T]) ValidateTyped(value T) error {
+	return r.rule.Validate(value)
+}
+
+// InRuleOf is a typed version of InRule.
+type InRuleOf[T comparable] struct {
+	rule InRule
+}
+
+// InOf returns a typed validation rule that checks if a value can be found in the given list of values.
+// Please refer to In for more details.
+func InOf[T comparable](values ...T) InRuleOf[T] {
+	return InRuleOf[T]{rule: In(interfaceSlice(values)...)}
+}
+
+// Error sets the error message for the rule.
+func (r InRuleOf[T]) Error(message string) InRuleOf[T] {
+	r.rule = r.rule.Error(message)
+	return r
+}
+
+// ErrorObject sets the error struct for the rule.
+func (r InRuleOf[T]) ErrorObject(err Error) InRuleOf[T] {
+	r.rule = r.rule.ErrorObject(err)
+	return r
+}
+
+// Validate checks if the given value is valid or not.
+func (r InRuleOf[T]) Validate(value interface{}) error {
+	return r.rule.Validate(value)
+}
+
+// ValidateTyped checks if the given value is valid or not.
+func (r InRuleOf[T]) ValidateTyped(value T) error {
+	return r.rule.Validate(value)
+}
+
+// NotInRuleOf is a typed version of NotInRule.
+type NotInRuleOf[T comparable] struct {
+	rule NotInRule
+}
+
+// NotInOf returns a typed validation rule that checks if a value is absent from the given list of values.
+// Please refer to NotIn for more details.
+func NotInOf[T comparable](values ...T) NotInRuleOf[T] {
+	return NotInRuleOf[T]{rule: NotIn(interfaceSlice(values)...)}
+}
+
+// Error sets the error message for the rule.
+func (r NotInRuleOf[T]) Error(message string) NotInRuleOf[T] {
+	r.rule = r.rule.Error(message)
+	return r
+}
+
+// ErrorObject sets the error struct for the rule.
+func (r NotInRuleOf[T]) ErrorObject(err Error) NotInRuleOf[T] {
+	r.rule = r.rule.ErrorObject(err)
+	return r
+}
+
+// Validate checks if the given value is valid or not.
+func (r NotInRuleOf[T]) Validate(value interface{}) error {
+	return r.rule.Validate(value)
+}
+
+// ValidateTyped checks if the given value is valid or not.
+func (r NotInRuleOf[T]) ValidateTyped(value T) error {
+	return r.rule.Validate(value)
+}
+
+// interfaceSlice converts a typed slice into a slice of interface{} values.
+func interfaceSlice[T any](values []T) []interface{} {
+	vs := make([]interface{}, len(values))
+	for i, v := range values {
+		vs[i] = v
+	}
+	return vs
+}
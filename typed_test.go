@@ -0,0 +1,107 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type status string
+
+func TestMinOfMaxOf(t *testing.T) {
+	date20000101 := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	date20000601 := time.Date(2000, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.NoError(t, MinOf(5).ValidateTyped(5))
+	assert.EqualError(t, MinOf(5).ValidateTyped(4), "must be no less than 5")
+	assert.EqualError(t, MinOf(5).Exclusive().ValidateTyped(5), "must be greater than 5")
+	assert.NoError(t, MinOf(5).ValidateTyped(0))
+	assert.EqualError(t, MaxOf(uint8(5)).ValidateTyped(6), "must be no greater than 5")
+	assert.EqualError(t, MaxOf(1.5).Exclusive().ValidateTyped(1.5), "must be less than 1.5")
+	assert.EqualError(t, MinOf(date20000601).ValidateTyped(date20000101), "must be no less than 2000-06-01 00:00:00 +0000 UTC")
+	assert.EqualError(t, MinOf(5).Error("too small").ValidateTyped(4), "too small")
+	assert.Equal(t, "code", MinOf(5).ErrorObject(NewError("code", "abc")).ValidateTyped(4).(Error).Code())
+
+	// typed rules are plain rules too
+	assert.EqualError(t, Validate(4, MinOf(5)), "must be no less than 5")
+}
+
+func TestLengthOf(t *testing.T) {
+	assert.NoError(t, LengthOf[string](1, 3).ValidateTyped("abc"))
+	assert.EqualError(t, LengthOf[string](1, 3).ValidateTyped("abcd"), "the length must be between 1 and 3")
+	assert.EqualError(t, LengthOf[[]byte](1, 3).ValidateTyped([]byte("abcd")), "the length must be between 1 and 3")
+	assert.NoError(t, RuneLengthOf[string](1, 3).ValidateTyped("äöü"))
+	assert.EqualError(t, LengthOf[string](1, 3).Error("bad").ValidateTyped("abcd"), "bad")
+	assert.Equal(t, "code", LengthOf[string](1, 3).ErrorObject(NewError("code", "abc")).ValidateTyped("abcd").(Error).Code())
+}
+
+func TestInOfNotInOf(t *testing.T) {
+	r := InOf[status]("active", "inactive")
+	assert.NoError(t, r.ValidateTyped("active"))
+	assert.NoError(t, r.ValidateTyped(""))
+	assert.EqualError(t, r.ValidateTyped("deleted"), "must be a valid value")
+	assert.EqualError(t, r.Error("bad").ValidateTyped("deleted"), "bad")
+	assert.Equal(t, "code", r.ErrorObject(NewError("code", "abc")).ValidateTyped("deleted").(Error).Code())
+
+	nr := NotInOf(1, 2)
+	assert.NoError(t, nr.ValidateTyped(3))
+	assert.EqualError(t, nr.ValidateTyped(2), "must not be in list")
+	assert.EqualError(t, nr.Error("bad").ValidateTyped(2), "bad")
+	assert.Equal(t, "code", nr.ErrorObject(NewError("code", "abc")).ValidateTyped(2).(Error).Code())
+}
+
+func TestTyped(t *testing.T) {
+	r := Typed[string](Required)
+	assert.EqualError(t, r.ValidateTyped(""), "cannot be blank")
+	assert.EqualError(t, r.Validate(""), "cannot be blank")
+	assert.NoError(t, r.ValidateTyped("abc"))
+
+	k := key(1)
+	rc := Typed[string](WithContext(func(ctx context.Context, value interface{}) error {
+		if ctx.Value(k) != value {
+			return errors.New("unexpected value")
+		}
+		return nil
+	}))
+	ctx := context.WithValue(context.Background(), k, "abc")
+	assert.NoError(t, rc.(RuleWithContext).ValidateWithContext(ctx, "abc"))
+	assert.EqualError(t, rc.(RuleWithContext).ValidateWithContext(ctx, "xyz"), "unexpected value")
+	assert.NoError(t, r.(RuleWithContext).ValidateWithContext(ctx, "xyz"))
+}
+
+func TestValidateValue(t *testing.T) {
+	assert.NoError(t, ValidateValue(5, Typed[int](Required), MinOf(1), MaxOf(10)))
+	assert.EqualError(t, ValidateValue(0, Typed[int](Required), MinOf(1)), "cannot be blank")
+	assert.EqualError(t, ValidateValue(11, MinOf(1), MaxOf(10)), "must be no greater than 10")
+	assert.NoError(t, ValidateValue(11, MinOf(1), Typed[int](Skip), MaxOf(10)))
+	assert.EqualError(t, ValidateValue(String123("abc"), InOf[String123]("abc")), "error 123")
+
+	k := key(1)
+	rc := Typed[string](WithContext(func(ctx context.Context, value interface{}) error {
+		if ctx.Value(k) != value {
+			return errors.New("unexpected value")
+		}
+		return nil
+	}))
+	ctx := context.WithValue(context.Background(), k, "abc")
+	assert.NoError(t, ValidateValueWithContext(ctx, "abc", LengthOf[string](1, 3), rc))
+	assert.EqualError(t, ValidateValueWithContext(ctx, "xyz", LengthOf[string](1, 3), rc), "unexpected value")
+}
+
+func TestFieldOf(t *testing.T) {
+	s := struct {
+		Name   string `json:"name"`
+		Age    int    `json:"age"`
+		Status status `json:"status"`
+	}{"", 17, "deleted"}
+
+	err := ValidateStruct(&s,
+		FieldOf(&s.Name, Typed[string](Required), LengthOf[string](1, 10)),
+		FieldOf(&s.Age, MinOf(18)),
+		FieldOf(&s.Status, InOf[status]("active")),
+	)
+	assert.EqualError(t, err, "age: must be no less than 18; name: cannot be blank; status: must be a valid value.")
+}
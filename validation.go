@@ -65,15 +65,32 @@ var (
 //    Return with the validation result.
 // 3. If the value being validated is a map/slice/array, and the element type implements `Validatable`,
 //    for each element call the element value's `Validate()`. Return with the validation result.
+//
+// The warnings reported by the rules (see Warn) do not stop the validation, nor are they returned:
+// please use ValidateWithContext along with WithWarnings to get them.
 func Validate(value interface{}, rules ...Rule) error {
+	return withoutWarnings(validate(value, rules...))
+}
+
+// validate performs the validation steps described in Validate, and returns the warnings found
+// along with the errors, in an ErrorList if needed.
+func validate(value interface{}, rules ...Rule) error {
+	var warnings ErrorList
 	for _, rule := range rules {
-		if s, ok := rule.(skipRule); ok && s.skip {
-			return nil
+		if s, ok := rule.(skipRule); ok && s.skips(nil, value) {
+			return joinWarnings(warnings, nil)
 		}
 		if err := rule.Validate(value); err != nil {
-			return err
+			if IsWarning(err) {
+				warnings = append(warnings, err)
+				continue
+			}
+			return joinWarnings(warnings, err)
 		}
 	}
+	if len(warnings) > 0 {
+		return joinWarnings(warnings, validate(value))
+	}
 
 	rv := reflect.ValueOf(value)
 	if (rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface) && rv.IsNil() {
@@ -94,7 +111,7 @@ func Validate(value interface{}, rules ...Rule) error {
 			return validateSlice(rv)
 		}
 	case reflect.Ptr, reflect.Interface:
-		return Validate(rv.Elem().Interface())
+		return validate(rv.Elem().Interface())
 	}
 
 	return nil
@@ -113,19 +130,56 @@ func Validate(value interface{}, rules ...Rule) error {
 //    for each element call the element value's `ValidateWithContext()`. Return with the validation result.
 // 5. If the value being validated is a map/slice/array, and the element type implements `Validatable`,
 //    for each element call the element value's `Validate()`. Return with the validation result.
+//
+// As for Validate, the warnings reported by the rules do not stop the validation, nor are they returned:
+// they are stored in the sink set by WithWarnings, if any.
+// If ctx carries a Translator (see WithTranslator), the messages of the returned validation errors are translated.
+// If ctx is canceled or its deadline is exceeded, the validation stops and an InternalError wrapping ctx.Err() is returned.
 func ValidateWithContext(ctx context.Context, value interface{}, rules ...Rule) error {
+	return reportWarnings(ctx, func(ctx context.Context) error {
+		return translateWithContext(ctx, validateWithContext(ctx, value, rules...))
+	})
+}
+
+// validateNested validates a value nested in another one, such as a struct field or an element, with the given
+// rules, using the context if it is not nil. Unlike Validate and ValidateWithContext, it returns the warnings found
+// along with the errors, so that they are reported with the errors of the outer value.
+func validateNested(ctx context.Context, value interface{}, rules ...Rule) error {
+	if ctx == nil {
+		return validate(value, rules...)
+	}
+	return translateWithContext(ctx, validateWithContext(ctx, value, rules...))
+}
+
+// validateWithContext performs the validation steps described in ValidateWithContext, without translating the result
+// and returning the warnings found along with the errors.
+func validateWithContext(ctx context.Context, value interface{}, rules ...Rule) error {
+	if err := contextError(ctx); err != nil {
+		return err
+	}
+
+	var warnings ErrorList
 	for _, rule := range rules {
-		if s, ok := rule.(skipRule); ok && s.skip {
-			return nil
+		if s, ok := rule.(skipRule); ok && s.skips(ctx, value) {
+			return joinWarnings(warnings, nil)
 		}
+		var err error
 		if rc, ok := rule.(RuleWithContext); ok {
-			if err := rc.ValidateWithContext(ctx, value); err != nil {
-				return err
+			err = rc.ValidateWithContext(ctx, value)
+		} else {
+			err = rule.Validate(value)
+		}
+		if err != nil {
+			if IsWarning(err) {
+				warnings = append(warnings, err)
+				continue
 			}
-		} else if err := rule.Validate(value); err != nil {
-			return err
+			return joinWarnings(warnings, err)
 		}
 	}
+	if len(warnings) > 0 {
+		return joinWarnings(warnings, validateWithContext(ctx, value))
+	}
 
 	rv := reflect.ValueOf(value)
 	if (rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface) && rv.IsNil() {
@@ -156,12 +210,38 @@ func ValidateWithContext(ctx context.Context, value interface{}, rules ...Rule)
 			return validateSlice(rv)
 		}
 	case reflect.Ptr, reflect.Interface:
-		return ValidateWithContext(ctx, rv.Elem().Interface())
+		return validateWithContext(ctx, rv.Elem().Interface())
 	}
 
 	return nil
 }
 
+// validateRules validates a value with the given rules only, and returns the first error found along with
+// the warnings reported before it. Unlike ValidateWithContext, it neither calls the Validate method of the value
+// nor translates the error.
+func validateRules(ctx context.Context, value interface{}, rules []Rule) error {
+	var warnings ErrorList
+	for _, rule := range rules {
+		if s, ok := rule.(skipRule); ok && s.skips(ctx, value) {
+			break
+		}
+		var err error
+		if rc, ok := rule.(RuleWithContext); ok && ctx != nil {
+			err = rc.ValidateWithContext(ctx, value)
+		} else {
+			err = rule.Validate(value)
+		}
+		if err != nil {
+			if IsWarning(err) {
+				warnings = append(warnings, err)
+				continue
+			}
+			return joinWarnings(warnings, err)
+		}
+	}
+	return joinWarnings(warnings, nil)
+}
+
 // validateMap validates a map of validatable elements
 func validateMap(rv reflect.Value) error {
 	errs := Errors{}
@@ -180,11 +260,34 @@ func validateMap(rv reflect.Value) error {
 
 // validateMapWithContext validates a map of validatable elements with the given context.
 func validateMapWithContext(ctx context.Context, rv reflect.Value) error {
+	if workers := parallelism(ctx); workers > 1 {
+		keys := rv.MapKeys()
+		names := make([]string, len(keys))
+		for i, key := range keys {
+			names[i] = fmt.Sprintf("%v", key.Interface())
+		}
+		return validateParallel(ctx, workers, names, func(i int) error {
+			if mv := rv.MapIndex(keys[i]).Interface(); mv != nil {
+				return mv.(ValidatableWithContext).ValidateWithContext(ctx)
+			}
+			return nil
+		})
+	}
+
 	errs := Errors{}
 	for _, key := range rv.MapKeys() {
+		if err := contextError(ctx); err != nil {
+			return err
+		}
 		if mv := rv.MapIndex(key).Interface(); mv != nil {
 			if err := mv.(ValidatableWithContext).ValidateWithContext(ctx); err != nil {
+				if ie, ok := err.(InternalError); ok && ie.InternalError() != nil {
+					return err
+				}
 				errs[fmt.Sprintf("%v", key.Interface())] = err
+				if stopOnFirstError(ctx) && !IsWarning(err) {
+					return errs
+				}
 			}
 		}
 	}
@@ -213,12 +316,34 @@ func validateSlice(rv reflect.Value) error {
 
 // validateSliceWithContext validates a slice/array of validatable elements with the given context.
 func validateSliceWithContext(ctx context.Context, rv reflect.Value) error {
-	errs := Errors{}
 	l := rv.Len()
+	if workers := parallelism(ctx); workers > 1 {
+		names := make([]string, l)
+		for i := range names {
+			names[i] = strconv.Itoa(i)
+		}
+		return validateParallel(ctx, workers, names, func(i int) error {
+			if ev := rv.Index(i).Interface(); ev != nil {
+				return ev.(ValidatableWithContext).ValidateWithContext(ctx)
+			}
+			return nil
+		})
+	}
+
+	errs := Errors{}
 	for i := 0; i < l; i++ {
+		if err := contextError(ctx); err != nil {
+			return err
+		}
 		if ev := rv.Index(i).Interface(); ev != nil {
 			if err := ev.(ValidatableWithContext).ValidateWithContext(ctx); err != nil {
+				if ie, ok := err.(InternalError); ok && ie.InternalError() != nil {
+					return err
+				}
 				errs[strconv.Itoa(i)] = err
+				if stopOnFirstError(ctx) && !IsWarning(err) {
+					return errs
+				}
 			}
 		}
 	}
@@ -230,6 +355,7 @@ func validateSliceWithContext(ctx context.Context, rv reflect.Value) error {
 
 type skipRule struct {
 	skip bool
+	lazy *lazyCondition
 }
 
 func (r skipRule) Validate(interface{}) error {
@@ -239,9 +365,28 @@ func (r skipRule) Validate(interface{}) error {
 // When determines if all rules following it should be skipped.
 func (r skipRule) When(condition bool) skipRule {
 	r.skip = condition
+	r.lazy = nil
+	return r
+}
+
+// WhenFunc sets a function that determines at validation time if all rules following it should be skipped.
+// The function is called with the validation context, or context.Background() if there is none.
+func (r skipRule) WhenFunc(condition func(ctx context.Context) bool) skipRule {
+	r.lazy = ctxCondition(condition)
+	return r
+}
+
+// WhenValue sets a predicate that determines if all rules following it should be skipped for the value being validated.
+func (r skipRule) WhenValue(predicate func(value interface{}) bool) skipRule {
+	r.lazy = valueCondition(predicate)
 	return r
 }
 
+// skips checks if the rules following the rule should be skipped when validating the given value.
+func (r skipRule) skips(ctx context.Context, value interface{}) bool {
+	return r.lazy.eval(ctx, value, r.skip)
+}
+
 type inlineRule struct {
 	f  RuleFunc
 	fc RuleWithContextFunc
@@ -76,6 +76,18 @@ func TestValidate(t *testing.T) {
 	assert.EqualError(t, err, "error abc")
 	err = Validate("abc", &validateAbc{}, Skip.When(false), &validateXyz{})
 	assert.EqualError(t, err, "error xyz")
+
+	skipAbc := Skip.WhenValue(func(value interface{}) bool { return value == "abc" })
+	err = Validate("abc", skipAbc, &validateXyz{})
+	assert.NoError(t, err)
+	err = Validate("xyz", skipAbc, &validateAbc{})
+	assert.EqualError(t, err, "error abc")
+
+	skipCtx := Skip.WhenFunc(func(ctx context.Context) bool { return ctx.Value(contains) != nil })
+	err = Validate("abc", skipCtx, &validateXyz{})
+	assert.EqualError(t, err, "error xyz")
+	err = ValidateWithContext(context.WithValue(context.Background(), contains, "abc"), "abc", skipCtx, &validateXyz{})
+	assert.NoError(t, err)
 }
 
 func stringEqual(str string) RuleFunc {
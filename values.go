@@ -0,0 +1,261 @@
+// Copyright 2016 Qiang Xue, 2022 Jellydator. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"context"
+	"errors"
+	"mime/multipart"
+	"reflect"
+	"strconv"
+)
+
+var (
+	// ErrNotValues is the error that the value being validated is not a set of query or form values.
+	ErrNotValues = errors.New("only url.Values, map[string][]string or a multipart form can be validated")
+
+	// ErrValueMultiple is the error returned when a single valued key has multiple values.
+	ErrValueMultiple = NewError("validation_value_multiple", "must not have multiple values")
+
+	// ErrValueNotInteger is the error returned when a value cannot be converted to an integer.
+	ErrValueNotInteger = NewError("validation_value_not_integer", "must be an integer")
+
+	// ErrValueNotNumber is the error returned when a value cannot be converted to a number.
+	ErrValueNotNumber = NewError("validation_value_not_number", "must be a number")
+)
+
+type (
+	// ValuesRule represents a rule set associated with query or form values.
+	ValuesRule struct {
+		keys           []*ValueKeyRules
+		allowExtraKeys bool
+	}
+
+	// ValueKeyRules represents a rule set associated with a key of query or form values.
+	ValueKeyRules struct {
+		key      string
+		optional bool
+		multi    bool
+		kind     reflect.Kind
+		rules    []Rule
+	}
+)
+
+// Values returns a validation rule that checks query or form values, i.e. url.Values, map[string][]string
+// or the values of a *multipart.Form. Use ValueKey() to specify the keys that need to be validated.
+// For example,
+//
+//	validation.Values(
+//	    validation.ValueKey("q", validation.Required, validation.Length(3, 50)),
+//	    validation.ValueKey("page", validation.Min(1)).Int().Optional(),
+//	    validation.ValueKey("tag", validation.Each(validation.In("a", "b"))).Multi().Optional(),
+//	)
+//
+// Like MapRule, keys are required unless Optional() is called, and keys that are not specified are reported
+// with ErrKeyUnexpected unless AllowExtraKeys() is called. Errors are keyed by the value keys.
+// A nil value is considered valid. Use the Required rule to make sure values are present.
+func Values(keys ...*ValueKeyRules) ValuesRule {
+	return ValuesRule{keys: keys}
+}
+
+// AllowExtraKeys configures the rule to ignore extra keys.
+func (r ValuesRule) AllowExtraKeys() ValuesRule {
+	r.allowExtraKeys = true
+	return r
+}
+
+// Validate checks if the given value is valid or not.
+func (r ValuesRule) Validate(value interface{}) error {
+	return r.ValidateWithContext(nil, value)
+}
+
+// ValidateWithContext checks if the given value is valid or not.
+func (r ValuesRule) ValidateWithContext(ctx context.Context, value interface{}) error {
+	values, ok := indirectValues(value)
+	if !ok {
+		return NewInternalError(ErrNotValues)
+	}
+	if values == nil {
+		// treat nil values as valid
+		return nil
+	}
+
+	errs := Errors{}
+	visited := make(map[string]struct{}, len(r.keys))
+
+	for _, kr := range r.keys {
+		if err := contextError(ctx); err != nil {
+			return err
+		}
+
+		visited[kr.key] = struct{}{}
+
+		vs := values[kr.key]
+		if len(vs) == 0 {
+			if !kr.optional {
+				errs[kr.key] = ErrKeyMissing
+			}
+			continue
+		}
+
+		v, err := kr.convert(vs)
+		if err == nil {
+			err = validateNested(ctx, v, kr.rules...)
+		}
+		if err != nil {
+			if ie, ok := err.(InternalError); ok && ie.InternalError() != nil {
+				return err
+			}
+
+			errs[kr.key] = err
+			if stopOnFirstError(ctx) && !IsWarning(err) {
+				return errs
+			}
+		}
+	}
+
+	if !r.allowExtraKeys {
+		for key := range values {
+			if _, ok := visited[key]; !ok {
+				errs[key] = ErrKeyUnexpected
+				if stopOnFirstError(ctx) {
+					return errs
+				}
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// ValueKey specifies a key of query or form values and the corresponding validation rules.
+// By default, the key must have a single value, which is validated as a string.
+func ValueKey(key string, rules ...Rule) *ValueKeyRules {
+	return &ValueKeyRules{
+		key:   key,
+		kind:  reflect.String,
+		rules: rules,
+	}
+}
+
+// Optional configures the rule to ignore the key if missing.
+func (r *ValueKeyRules) Optional() *ValueKeyRules {
+	r.optional = true
+	return r
+}
+
+// Multi configures the rule to accept multiple values for the key. The rules then validate a slice
+// containing all values, so Length checks the number of values and Each validates every value.
+func (r *ValueKeyRules) Multi() *ValueKeyRules {
+	r.multi = true
+	return r
+}
+
+// Int configures the rule to convert the values into int64 before validating them,
+// so that rules such as Min, Max or MultipleOf can be used. An empty value is converted into 0.
+func (r *ValueKeyRules) Int() *ValueKeyRules {
+	r.kind = reflect.Int64
+	return r
+}
+
+// Float configures the rule to convert the values into float64 before validating them,
+// so that rules such as Min, Max or MultipleOf can be used. An empty value is converted into 0.
+func (r *ValueKeyRules) Float() *ValueKeyRules {
+	r.kind = reflect.Float64
+	return r
+}
+
+// convert converts the values of the key into the value that the rules validate.
+func (r *ValueKeyRules) convert(vs []string) (interface{}, error) {
+	if !r.multi {
+		if len(vs) > 1 {
+			return nil, ErrValueMultiple
+		}
+		return convertValue(vs[0], r.kind)
+	}
+
+	if r.kind == reflect.String {
+		return vs, nil
+	}
+
+	res := reflect.MakeSlice(reflect.SliceOf(reflect.TypeOf(zeroValues[r.kind])), len(vs), len(vs))
+	errs := Errors{}
+	for i, s := range vs {
+		v, err := convertValue(s, r.kind)
+		if err != nil {
+			errs[strconv.Itoa(i)] = err
+			continue
+		}
+		res.Index(i).Set(reflect.ValueOf(v))
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return res.Interface(), nil
+}
+
+// zeroValues holds the values that empty strings are converted into.
+var zeroValues = map[reflect.Kind]interface{}{
+	reflect.Int64:   int64(0),
+	reflect.Float64: float64(0),
+}
+
+// convertValue converts a single value into the given kind.
+func convertValue(s string, kind reflect.Kind) (interface{}, error) {
+	if s == "" && kind != reflect.String {
+		return zeroValues[kind], nil
+	}
+	switch kind {
+	case reflect.Int64:
+		v, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, ErrValueNotInteger
+		}
+		return v, nil
+	case reflect.Float64:
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, ErrValueNotNumber
+		}
+		return v, nil
+	}
+	return s, nil
+}
+
+var valuesType = reflect.TypeOf(map[string][]string(nil))
+
+// indirectValues returns the query or form values held by value.
+func indirectValues(value interface{}) (map[string][]string, bool) {
+	switch v := value.(type) {
+	case *multipart.Form:
+		if v == nil {
+			return nil, true
+		}
+		return v.Value, true
+	case multipart.Form:
+		return v.Value, true
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			t := rv.Type().Elem()
+			return nil, t.Kind() == reflect.Map && t.ConvertibleTo(valuesType)
+		}
+		rv = rv.Elem()
+	}
+	// url.Values, http.Header and other named types are converted
+	if !rv.IsValid() || rv.Kind() != reflect.Map || !rv.Type().ConvertibleTo(valuesType) {
+		return nil, false
+	}
+	if rv.IsNil() {
+		return nil, true
+	}
+	return rv.Convert(valuesType).Interface().(map[string][]string), true
+}
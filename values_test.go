@@ -0,0 +1,78 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestValues(t *testing.T) {
+	var v0 url.Values
+	v1 := url.Values{"q": {"abc"}, "page": {"2"}, "price": {"1.5"}, "tag": {"a", "b"}, "ids": {"1", "x", "3"}, "empty": {""}}
+	tests := []struct {
+		tag   string
+		value interface{}
+		rule  ValuesRule
+		err   string
+	}{
+		// string values
+		{"t1.1", v1, Values(ValueKey("q", Required, Length(3, 3))).AllowExtraKeys(), ""},
+		{"t1.2", v1, Values(ValueKey("q", Length(1, 2))).AllowExtraKeys(), "q: the length must be between 1 and 2."},
+		{"t1.3", v1, Values(ValueKey("empty", Required)).AllowExtraKeys(), "empty: cannot be blank."},
+		{"t1.4", v1, Values(ValueKey("page", In("2"))).AllowExtraKeys(), ""},
+		// single vs multi values
+		{"t2.1", v1, Values(ValueKey("tag")).AllowExtraKeys(), "tag: must not have multiple values."},
+		{"t2.2", v1, Values(ValueKey("tag", Length(2, 2), Each(In("a", "b"))).Multi()).AllowExtraKeys(), ""},
+		{"t2.3", v1, Values(ValueKey("tag", Each(In("a"))).Multi()).AllowExtraKeys(), "tag: (1: must be a valid value.)."},
+		{"t2.4", v1, Values(ValueKey("q", Length(2, 0)).Multi()).AllowExtraKeys(), "q: the length must be no less than 2."},
+		// coercion
+		{"t3.1", v1, Values(ValueKey("page", Min(1), Max(5), MultipleOf(2)).Int()).AllowExtraKeys(), ""},
+		{"t3.2", v1, Values(ValueKey("page", Min(3)).Int()).AllowExtraKeys(), "page: must be no less than 3."},
+		{"t3.3", v1, Values(ValueKey("price", Min(1.0), Max(2.0)).Float()).AllowExtraKeys(), ""},
+		{"t3.4", v1, Values(ValueKey("price", Max(1.0)).Float()).AllowExtraKeys(), "price: must be no greater than 1."},
+		{"t3.5", v1, Values(ValueKey("price").Int()).AllowExtraKeys(), "price: must be an integer."},
+		{"t3.6", v1, Values(ValueKey("q").Float()).AllowExtraKeys(), "q: must be a number."},
+		{"t3.7", v1, Values(ValueKey("ids").Int().Multi()).AllowExtraKeys(), "ids: (1: must be an integer.)."},
+		{"t3.8", url.Values{"ids": {"1", "3"}}, Values(ValueKey("ids", Each(Max(2))).Int().Multi()), "ids: (1: must be no greater than 2.)."},
+		{"t3.9", v1, Values(ValueKey("empty", Min(1)).Int()).AllowExtraKeys(), ""},
+		{"t3.10", v1, Values(ValueKey("empty", Required).Int()).AllowExtraKeys(), "empty: cannot be blank."},
+		// required and optional keys
+		{"t4.1", v1, Values(ValueKey("x")).AllowExtraKeys(), "x: required key is missing."},
+		{"t4.2", v1, Values(ValueKey("x").Optional()).AllowExtraKeys(), ""},
+		{"t4.3", url.Values{"x": {}}, Values(ValueKey("x")), "x: required key is missing."},
+		// unexpected keys
+		{"t5.1", url.Values{"q": {"abc"}, "x": {"1"}}, Values(ValueKey("q")), "x: key not expected."},
+		{"t5.2", url.Values{"q": {"abc"}, "x": {"1"}}, Values(ValueKey("q")).AllowExtraKeys(), ""},
+		// value types
+		{"t6.1", &v1, Values().AllowExtraKeys(), ""},
+		{"t6.2", v0, Values(ValueKey("q")), ""},
+		{"t6.3", map[string][]string{"q": {"a"}}, Values(ValueKey("q", Length(2, 0))), "q: the length must be no less than 2."},
+		{"t6.4", http.Header{"Q": {"a"}}, Values(ValueKey("Q", Length(2, 0))), "Q: the length must be no less than 2."},
+		{"t6.5", &multipart.Form{Value: map[string][]string{"q": {"a"}}}, Values(ValueKey("q", Length(2, 0))), "q: the length must be no less than 2."},
+		{"t6.6", (*multipart.Form)(nil), Values(ValueKey("q")), ""},
+		{"t6.7", map[string]string{"q": "a"}, Values(), ErrNotValues.Error()},
+		{"t6.8", nil, Values(), ErrNotValues.Error()},
+		{"t6.9", (*url.Values)(nil), Values(ValueKey("q")), ""},
+		{"t6.10", (*http.Header)(nil), Values(ValueKey("q")), ""},
+		{"t6.11", (*string)(nil), Values(), ErrNotValues.Error()},
+		// internal errors
+		{"t7.1", v1, Values(ValueKey("q", By(func(interface{}) error { return NewInternalError(errors.New("failed")) }))).AllowExtraKeys(), "failed"},
+	}
+	for _, test := range tests {
+		err := Validate(test.value, test.rule)
+		assertError(t, test.err, err, test.tag)
+		err = ValidateWithContext(context.Background(), test.value, test.rule)
+		assertError(t, test.err, err, test.tag)
+	}
+
+	err := Values(ValueKey("q", WithContext(func(ctx context.Context, value interface{}) error {
+		if ctx.Value(contains) == value {
+			return errors.New("forbidden")
+		}
+		return nil
+	}))).ValidateWithContext(context.WithValue(context.Background(), contains, "abc"), url.Values{"q": {"abc"}})
+	assertError(t, "q: forbidden.", err, "t8")
+}
@@ -11,9 +11,31 @@ func When(condition bool, rules ...Rule) WhenRule {
 	}
 }
 
+// WhenFunc returns a validation rule that executes the given list of rules when the given function
+// returns true. Unlike When, the condition is evaluated at validation time with the validation context,
+// which allows a rule to be declared once and reused. If no context is given, context.Background() is used.
+func WhenFunc(condition func(ctx context.Context) bool, rules ...Rule) WhenRule {
+	return WhenRule{
+		lazy:      ctxCondition(condition),
+		rules:     rules,
+		elseRules: []Rule{},
+	}
+}
+
+// WhenValue returns a validation rule that executes the given list of rules when the given predicate
+// returns true for the value being validated.
+func WhenValue(predicate func(value interface{}) bool, rules ...Rule) WhenRule {
+	return WhenRule{
+		lazy:      valueCondition(predicate),
+		rules:     rules,
+		elseRules: []Rule{},
+	}
+}
+
 // WhenRule is a validation rule that executes the given list of rules when the condition is true.
 type WhenRule struct {
 	condition bool
+	lazy      *lazyCondition
 	rules     []Rule
 	elseRules []Rule
 }
@@ -25,17 +47,10 @@ func (r WhenRule) Validate(value interface{}) error {
 
 // ValidateWithContext checks if the condition is true and if so, it validates the value using the specified rules.
 func (r WhenRule) ValidateWithContext(ctx context.Context, value interface{}) error {
-	if r.condition {
-		if ctx == nil {
-			return Validate(value, r.rules...)
-		}
-		return ValidateWithContext(ctx, value, r.rules...)
-	}
-
-	if ctx == nil {
-		return Validate(value, r.elseRules...)
+	if r.lazy.eval(ctx, value, r.condition) {
+		return validateNested(ctx, value, r.rules...)
 	}
-	return ValidateWithContext(ctx, value, r.elseRules...)
+	return validateNested(ctx, value, r.elseRules...)
 }
 
 // Else returns a validation rule that executes the given list of rules when the condition is false.
@@ -43,3 +58,46 @@ func (r WhenRule) Else(rules ...Rule) WhenRule {
 	r.elseRules = rules
 	return r
 }
+
+// nestedRules returns the rules and the else rules.
+func (r WhenRule) nestedRules() [][]Rule {
+	return [][]Rule{r.rules, r.elseRules}
+}
+
+// withNestedRules returns a copy of the rule with the given rules and else rules.
+func (r WhenRule) withNestedRules(rules [][]Rule) Rule {
+	r.rules, r.elseRules = rules[0], rules[1]
+	return r
+}
+
+// lazyCondition is a condition that is evaluated at validation time.
+// Rules hold it by pointer, so that rules without slices, such as Required, remain comparable.
+// A nil *lazyCondition means that the condition was given as a plain bool.
+type lazyCondition func(ctx context.Context, value interface{}) bool
+
+// ctxCondition returns a lazyCondition that calls the given function with the validation context.
+func ctxCondition(f func(ctx context.Context) bool) *lazyCondition {
+	c := lazyCondition(func(ctx context.Context, _ interface{}) bool {
+		return f(ctx)
+	})
+	return &c
+}
+
+// valueCondition returns a lazyCondition that calls the given predicate with the value being validated.
+func valueCondition(f func(value interface{}) bool) *lazyCondition {
+	c := lazyCondition(func(_ context.Context, value interface{}) bool {
+		return f(value)
+	})
+	return &c
+}
+
+// eval evaluates the condition. If the condition is nil, the given fallback is returned.
+func (c *lazyCondition) eval(ctx context.Context, value interface{}, fallback bool) bool {
+	if c == nil {
+		return fallback
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return (*c)(ctx, value)
+}
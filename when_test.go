@@ -98,3 +98,66 @@ func TestWhenWithContext(t *testing.T) {
 		assertError(t, test.err, err, test.tag)
 	}
 }
+
+func TestWhenFunc(t *testing.T) {
+	abcRule := NewStringRule(abcValidation, "wrong_abc")
+	hasAbc := func(ctx context.Context) bool {
+		v, _ := ctx.Value(contains).(string)
+		return v == "abc"
+	}
+	// the same rule is reused with different contexts
+	rule := WhenFunc(hasAbc, abcRule).Else(Required)
+
+	ctx1 := context.WithValue(context.Background(), contains, "abc")
+	ctx2 := context.WithValue(context.Background(), contains, "xyz")
+
+	tests := []struct {
+		tag   string
+		ctx   context.Context
+		value interface{}
+		err   string
+	}{
+		{"t1.1", ctx1, "abc", ""},
+		{"t1.2", ctx1, "xyz", "wrong_abc"},
+		{"t1.3", ctx2, "xyz", ""},
+		{"t1.4", ctx2, "", "cannot be blank"},
+		{"t1.5", nil, "", "cannot be blank"},
+	}
+
+	for _, test := range tests {
+		var err error
+		if test.ctx == nil {
+			err = Validate(test.value, rule)
+		} else {
+			err = ValidateWithContext(test.ctx, test.value, rule)
+		}
+		assertError(t, test.err, err, test.tag)
+	}
+}
+
+func TestWhenValue(t *testing.T) {
+	abcRule := NewStringRule(abcValidation, "wrong_abc")
+	isLong := func(value interface{}) bool {
+		s, _ := value.(string)
+		return len(s) > 2
+	}
+	rule := WhenValue(isLong, abcRule).Else(Length(0, 1))
+
+	tests := []struct {
+		tag   string
+		value interface{}
+		err   string
+	}{
+		{"t1.1", "abc", ""},
+		{"t1.2", "xyz", "wrong_abc"},
+		{"t1.3", "a", ""},
+		{"t1.4", "ab", "the length must be no more than 1"},
+	}
+
+	for _, test := range tests {
+		err := Validate(test.value, rule)
+		assertError(t, test.err, err, test.tag)
+		err = ValidateWithContext(context.Background(), test.value, rule)
+		assertError(t, test.err, err, test.tag)
+	}
+}